@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/manikandareas/go-clean-architecture/internal/config"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"os"
+	"strconv"
+)
+
+const usage = `Usage: migrate [flags] <command> [argument]
+
+Commands:
+  up [N]         apply all pending migrations, or only the next N
+  down [N]       roll back the last N migrations (default 1)
+  status         print the applied and the latest available version
+  force VERSION  mark VERSION as applied and clean without running it
+
+A database created by the former AutoMigrate has to be baselined once, as
+described in db/migrations/README.md.
+
+Flags:
+`
+
+func main() {
+	configPath := flag.String("config", "", "path to the base configuration file (default config.json)")
+	profile := flag.String("profile", "", "configuration profile merged over the base, e.g. dev or prod (env APP_PROFILE)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	command := flag.Arg(0)
+	if command != "up" && command != "down" && command != "status" && command != "force" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	viperConfig := config.NewViper(*configPath, *profile)
+	translator := config.NewTranslator()
+	appConfig, err := config.NewAppConfig(viperConfig, config.NewValidator(viperConfig, translator), translator)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	logging, err := config.NewLogging(appConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer config.CloseLogging(logging)
+	log := logging.Root
+
+	m, err := config.NewMigrate(appConfig)
+	if err != nil {
+		log.Fatalf("Failed to open migrations: %v", err)
+	}
+
+	err = run(m, appConfig, command, flag.Arg(1))
+	m.Close()
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Info("No migrations to apply")
+	} else if err != nil {
+		log.Fatalf("Failed to %s: %v", command, err)
+	}
+}
+
+func run(m *migrate.Migrate, appConfig *model.AppConfig, command string, argument string) error {
+	switch command {
+	case "up":
+		if argument == "" {
+			return m.Up()
+		}
+		steps, err := parseSteps(argument)
+		if err != nil {
+			return err
+		}
+		return m.Steps(steps)
+	case "down":
+		if argument == "" {
+			return m.Steps(-1)
+		}
+		steps, err := parseSteps(argument)
+		if err != nil {
+			return err
+		}
+		return m.Steps(-steps)
+	case "force":
+		version, err := strconv.Atoi(argument)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", argument)
+		}
+		return m.Force(version)
+	default:
+		latest, err := config.LatestMigration(appConfig)
+		if err != nil {
+			return err
+		}
+		version, dirty, err := m.Version()
+		if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+			return err
+		}
+		fmt.Printf("version: %d\nlatest: %d\ndirty: %t\n", version, latest, dirty)
+		return nil
+	}
+}
+
+func parseSteps(argument string) (int, error) {
+	steps, err := strconv.Atoi(argument)
+	if err != nil || steps < 1 {
+		return 0, fmt.Errorf("invalid number of migrations %q", argument)
+	}
+	return steps, nil
+}
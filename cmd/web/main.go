@@ -1,32 +1,123 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"github.com/gofiber/fiber/v2"
 	"github.com/manikandareas/go-clean-architecture/internal/config"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
 	"github.com/manikandareas/go-clean-architecture/pkg"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	viperConfig := config.NewViper()
-	log := config.NewLogger(viperConfig)
+	configPath := flag.String("config", "", "path to the base configuration file (default config.json)")
+	profile := flag.String("profile", "", "configuration profile merged over the base, e.g. dev or prod (env APP_PROFILE)")
+	flag.Parse()
 
-	db := config.NewDatabase(viperConfig, log)
-	validate := config.NewValidator(viperConfig)
-	app := config.NewFiber(viperConfig)
-	jwtService := pkg.NewJwtService(viperConfig)
+	viperConfig := config.NewViper(*configPath, *profile)
+	translator := config.NewTranslator()
+	validate := config.NewValidator(viperConfig, translator)
+	appConfig, err := config.NewAppConfig(viperConfig, validate, translator)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	logging, err := config.NewLogging(appConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	log := logging.Root
+	tracerProvider, err := config.NewTracerProvider(appConfig)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err.Error())
+	}
+
+	metrics := pkg.NewMetrics()
+	db := config.NewDatabase(appConfig, logging.Package(model.LogPackageGorm))
+	if err := db.Use(&pkg.GormTracing{}); err != nil {
+		log.Fatalf("Failed to instrument database: %v", err.Error())
+	}
+	if err := db.Use(&pkg.GormMetrics{Metrics: metrics}); err != nil {
+		log.Fatalf("Failed to instrument database: %v", err.Error())
+	}
+	if err := metrics.RegisterDatabase(db, appConfig.Database.Name); err != nil {
+		log.Fatalf("Failed to instrument database: %v", err.Error())
+	}
+	if appConfig.Database.AutoMigrate {
+		if err := config.MigrateUp(appConfig); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err.Error())
+		}
+	}
+	if err := config.CheckMigration(appConfig); err != nil {
+		log.Fatalf("Refusing to start: %v", err.Error())
+	}
+	app := config.NewFiber(appConfig, translator)
+	metricsApp := config.NewMetricsFiber(appConfig, translator)
+	settings := pkg.NewSettings(appConfig)
+	jwtService := pkg.NewJwtService(settings)
+	cursorService := pkg.NewCursorService(appConfig)
 
+	jobs, stopJobs := context.WithCancel(context.Background())
 	config.Bootstrap(&config.BootstrapConfig{
-		DB:         db,
-		App:        app,
-		Log:        log,
-		Validate:   validate,
-		Config:     viperConfig,
-		JwtService: jwtService,
+		Context:       jobs,
+		DB:            db,
+		App:           app,
+		MetricsApp:    metricsApp,
+		Logging:       logging,
+		Validate:      validate,
+		Config:        appConfig,
+		Settings:      settings,
+		JwtService:    jwtService,
+		CursorService: cursorService,
+		Metrics:       metrics,
 	})
+	config.WatchConfig(jobs, viperConfig, *profile, validate, translator, settings, logging)
 
-	webPort := viperConfig.GetInt32("web.port")
-	if err := app.Listen(fmt.Sprintf(":%d", webPort)); err != nil {
-		log.Fatalf("Failed to start server: %v", err.Error())
+	listenErr := make(chan error, 2)
+	go func() {
+		listenErr <- app.Listen(fmt.Sprintf(":%d", appConfig.Web.Port))
+	}()
+	// prefork children would all try to bind the metrics port, which only the
+	// parent process does
+	if metricsApp != nil && !fiber.IsChild() {
+		go func() {
+			listenErr <- metricsApp.Listen(fmt.Sprintf(":%d", appConfig.Metrics.Port))
+		}()
 	}
 
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+	// a server that cannot listen still closes tracing, the pool and the log
+	// files, but exits with a failure
+	exitCode := 0
+	select {
+	case <-quit:
+	case err := <-listenErr:
+		log.Errorf("Failed to start server: %v", err)
+		exitCode = 1
+	}
+
+	// stop accepting connections and let in-flight requests, and with them
+	// their transactions, finish before the pool goes away
+	log.Info("Shutting down server")
+	if err := app.ShutdownWithTimeout(appConfig.Web.ShutdownTimeout); err != nil {
+		log.Errorf("Failed to shut down server: %v", err.Error())
+	}
+	if metricsApp != nil {
+		if err := metricsApp.ShutdownWithTimeout(appConfig.Web.ShutdownTimeout); err != nil {
+			log.Errorf("Failed to shut down metrics server: %v", err.Error())
+		}
+	}
+	stopJobs()
+	config.CloseTracerProvider(tracerProvider, log)
+	config.CloseDatabase(db, log)
+	log.Info("Server stopped")
+	config.CloseLogging(logging)
+	os.Exit(exitCode)
 }
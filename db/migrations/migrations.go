@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned SQL migrations, one directory per
+// supported database driver, so that the binaries can apply them without the
+// source tree being present.
+package migrations
+
+import "embed"
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var FS embed.FS
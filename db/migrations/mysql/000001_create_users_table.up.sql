@@ -0,0 +1,12 @@
+CREATE TABLE users
+(
+    id         VARCHAR(191) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    email      VARCHAR(100) NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    token      TEXT         NULL,
+    created_at DATETIME(3)  NULL,
+    updated_at DATETIME(3)  NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_users_email (email)
+) ENGINE = InnoDB;
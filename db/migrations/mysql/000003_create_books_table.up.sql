@@ -0,0 +1,14 @@
+CREATE TABLE books
+(
+    id         VARCHAR(191) NOT NULL,
+    title      VARCHAR(255) NOT NULL,
+    author_id  VARCHAR(191) NOT NULL,
+    owner_id   VARCHAR(191) NULL,
+    created_at DATETIME(3)  NULL,
+    updated_at DATETIME(3)  NULL,
+    PRIMARY KEY (id),
+    INDEX idx_books_author_id (author_id),
+    INDEX idx_books_owner_id (owner_id),
+    INDEX idx_books_created_at (created_at, id),
+    CONSTRAINT fk_authors_books FOREIGN KEY (author_id) REFERENCES authors (id) ON UPDATE CASCADE ON DELETE RESTRICT
+) ENGINE = InnoDB;
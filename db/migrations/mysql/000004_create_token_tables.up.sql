@@ -0,0 +1,27 @@
+CREATE TABLE revoked_tokens
+(
+    id         VARCHAR(191) NOT NULL,
+    user_id    VARCHAR(191) NOT NULL,
+    expires_at DATETIME(3)  NOT NULL,
+    created_at DATETIME(3)  NULL,
+    PRIMARY KEY (id),
+    INDEX idx_revoked_tokens_user_id (user_id),
+    INDEX idx_revoked_tokens_expires_at (expires_at)
+) ENGINE = InnoDB;
+
+CREATE TABLE refresh_tokens
+(
+    id         VARCHAR(191) NOT NULL,
+    family_id  VARCHAR(191) NOT NULL,
+    user_id    VARCHAR(191) NOT NULL,
+    token_hash VARCHAR(64)  NOT NULL,
+    used_at    DATETIME(3)  NULL,
+    revoked_at DATETIME(3)  NULL,
+    expires_at DATETIME(3)  NOT NULL,
+    created_at DATETIME(3)  NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_refresh_tokens_token_hash (token_hash),
+    INDEX idx_refresh_tokens_family_id (family_id),
+    INDEX idx_refresh_tokens_user_id (user_id),
+    INDEX idx_refresh_tokens_expires_at (expires_at)
+) ENGINE = InnoDB;
@@ -0,0 +1,13 @@
+CREATE TABLE users
+(
+    id         VARCHAR(191) NOT NULL,
+    password   VARCHAR(255) NOT NULL,
+    email      VARCHAR(100) NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    token      TEXT         NULL,
+    created_at TIMESTAMPTZ  NULL,
+    updated_at TIMESTAMPTZ  NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX idx_users_email ON users (email);
@@ -0,0 +1,15 @@
+CREATE TABLE books
+(
+    id         VARCHAR(191) NOT NULL,
+    title      VARCHAR(255) NOT NULL,
+    author_id  VARCHAR(191) NOT NULL,
+    owner_id   VARCHAR(191) NULL,
+    created_at TIMESTAMPTZ  NULL,
+    updated_at TIMESTAMPTZ  NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_authors_books FOREIGN KEY (author_id) REFERENCES authors (id) ON UPDATE CASCADE ON DELETE RESTRICT
+);
+
+CREATE INDEX idx_books_author_id ON books (author_id);
+CREATE INDEX idx_books_owner_id ON books (owner_id);
+CREATE INDEX idx_books_created_at ON books (created_at, id);
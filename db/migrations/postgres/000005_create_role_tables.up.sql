@@ -0,0 +1,38 @@
+CREATE TABLE roles
+(
+    id         VARCHAR(191) NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ  NULL,
+    updated_at TIMESTAMPTZ  NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX idx_roles_name ON roles (name);
+
+CREATE TABLE permissions
+(
+    id         VARCHAR(191) NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ  NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX idx_permissions_name ON permissions (name);
+
+CREATE TABLE role_permissions
+(
+    role_id       VARCHAR(191) NOT NULL,
+    permission_id VARCHAR(191) NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
+
+CREATE TABLE user_roles
+(
+    user_id VARCHAR(191) NOT NULL,
+    role_id VARCHAR(191) NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
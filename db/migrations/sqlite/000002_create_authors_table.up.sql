@@ -0,0 +1,9 @@
+CREATE TABLE authors
+(
+    id         VARCHAR(191) NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    bio        TEXT         NULL,
+    created_at DATETIME     NULL,
+    updated_at DATETIME     NULL,
+    PRIMARY KEY (id)
+);
@@ -0,0 +1,29 @@
+CREATE TABLE revoked_tokens
+(
+    id         VARCHAR(191) NOT NULL,
+    user_id    VARCHAR(191) NOT NULL,
+    expires_at DATETIME     NOT NULL,
+    created_at DATETIME     NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_revoked_tokens_user_id ON revoked_tokens (user_id);
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+CREATE TABLE refresh_tokens
+(
+    id         VARCHAR(191) NOT NULL,
+    family_id  VARCHAR(191) NOT NULL,
+    user_id    VARCHAR(191) NOT NULL,
+    token_hash VARCHAR(64)  NOT NULL,
+    used_at    DATETIME     NULL,
+    revoked_at DATETIME     NULL,
+    expires_at DATETIME     NOT NULL,
+    created_at DATETIME     NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens (expires_at);
@@ -1,51 +1,105 @@
 package config
 
 import (
+	"context"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/manikandareas/go-clean-architecture/internal/delivery/http"
 	"github.com/manikandareas/go-clean-architecture/internal/delivery/http/middleware"
 	"github.com/manikandareas/go-clean-architecture/internal/delivery/http/route"
-	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
 	"github.com/manikandareas/go-clean-architecture/internal/repository"
 	"github.com/manikandareas/go-clean-architecture/internal/usecase"
 	"github.com/manikandareas/go-clean-architecture/pkg"
-	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gorm.io/gorm"
+	"time"
 )
 
 type BootstrapConfig struct {
-	DB         *gorm.DB
-	App        *fiber.App
-	Log        *logrus.Logger
-	Validate   *validator.Validate
-	Config     *viper.Viper
-	JwtService *pkg.JwtService
+	// ends the background jobs, cancelled on shutdown
+	Context       context.Context
+	DB            *gorm.DB
+	App           *fiber.App
+	MetricsApp    *fiber.App
+	Logging       *Logging
+	Validate      *validator.Validate
+	Config        *model.AppConfig
+	Settings      *pkg.Settings
+	JwtService    *pkg.JwtService
+	CursorService *pkg.CursorService
+	Metrics       *pkg.Metrics
 }
 
 func Bootstrap(config *BootstrapConfig) {
-	Migrator(config.DB, &entity.Book{}, &entity.User{})
-
+	repositoryLog := config.Logging.Package(model.LogPackageRepository)
+	useCaseLog := config.Logging.Package(model.LogPackageUseCase)
+	httpLog := config.Logging.Package(model.LogPackageHttp)
 	// setup	repository
-	bookRepository := repository.NewBookRepository(config.Log)
-	userRepository := repository.NewUserRepository(config.Log)
+	txManager := repository.NewTxManager(config.DB, repositoryLog)
+	bookRepository := repository.NewBookRepository(config.DB, repositoryLog)
+	authorRepository := repository.NewAuthorRepository(config.DB, repositoryLog)
+	userRepository := repository.NewUserRepository(config.DB, repositoryLog)
+	revokedTokenRepository := repository.NewRevokedTokenRepository(config.DB, repositoryLog)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(config.DB, repositoryLog)
+	roleRepository := repository.NewRoleRepository(config.DB, repositoryLog)
+	permissionRepository := repository.NewPermissionRepository(config.DB, repositoryLog)
 	// setup use case
-	bookUseCase := usecase.NewBookUseCase(config.DB, config.Log, config.Validate, bookRepository)
-	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, userRepository, config.JwtService)
+	bookUseCase := usecase.NewBookUseCase(txManager, useCaseLog, config.Validate, bookRepository, authorRepository, config.CursorService)
+	authorUseCase := usecase.NewAuthorUseCase(txManager, useCaseLog, config.Validate, authorRepository, bookRepository)
+	userUseCase := usecase.NewUserUseCase(txManager, useCaseLog, config.Validate, userRepository, revokedTokenRepository, refreshTokenRepository, roleRepository, config.JwtService, config.Metrics)
+	roleUseCase := usecase.NewRoleUseCase(txManager, useCaseLog, config.Validate, roleRepository, permissionRepository, userRepository)
+	// prefork children would only repeat the seeding and the jobs of the
+	// parent process, which seeds before it starts them
+	if !fiber.IsChild() {
+		if err := roleUseCase.Seed(context.Background(), config.Config.Rbac.Admins); err != nil {
+			panic(fmt.Errorf("failed to seed roles: %v", err.Error()))
+		}
+		StartTokenCleanup(config.Context, userUseCase, config.Config.Jwt.CleanupInterval)
+	}
+	healthUseCase := usecase.NewHealthUseCase(useCaseLog, 2*time.Second,
+		&repository.DatabaseHealthChecker{DB: config.DB},
+		&MigrationHealthChecker{Config: config.Config},
+		&ConfigHealthChecker{Settings: config.Settings, Validate: config.Validate},
+	)
 	//	setup controller
-	bookController := http.NewBookController(bookUseCase, config.Log)
-	userController := http.NewUserController(userUseCase, config.Log)
+	bookController := http.NewBookController(bookUseCase, httpLog)
+	authorController := http.NewAuthorController(authorUseCase, httpLog)
+	userController := http.NewUserController(userUseCase, httpLog)
+	roleController := http.NewRoleController(roleUseCase, httpLog)
+	healthController := http.NewHealthController(healthUseCase, httpLog)
 	// setup middleware
 	authMiddleware := middleware.NewAuth(userUseCase)
 	refreshTokenMiddleware := middleware.NewRefreshToken(userUseCase)
+	rateLimitMiddleware := middleware.NewRateLimit(config.Settings)
+	featureMiddleware := middleware.NewFeature(config.Settings)
+	requestIdMiddleware := middleware.NewRequestID(httpLog)
+	routeLogMiddleware := middleware.NewRouteLog(httpLog)
+	accessLogMiddleware := middleware.NewAccessLog(httpLog)
+	metricsMiddleware := middleware.NewMetrics(config.Metrics)
+	tracingMiddleware := middleware.NewTracing()
 	// setup route
 	routeConfig := route.RouteConfig{
 		App:                    config.App,
+		MetricsApp:             config.MetricsApp,
+		RequestIDMiddleware:    requestIdMiddleware,
+		RouteLogMiddleware:     routeLogMiddleware,
+		AccessLogMiddleware:    accessLogMiddleware,
 		BookController:         bookController,
+		AuthorController:       authorController,
 		UserController:         userController,
+		RoleController:         roleController,
+		HealthController:       healthController,
 		AuthMiddleware:         authMiddleware,
 		RefreshTokenMiddleware: refreshTokenMiddleware,
+		RateLimitMiddleware:    rateLimitMiddleware,
+		FeatureMiddleware:      featureMiddleware,
+		MetricsMiddleware:      metricsMiddleware,
+		TracingMiddleware:      tracingMiddleware,
+		MetricsHandler:         adaptor.HTTPHandler(promhttp.HandlerFor(config.Metrics.Registry, promhttp.HandlerOpts{})),
+		MetricsAllowMiddleware: middleware.NewAllowNetworks(config.Config.Metrics.AllowedNetworks),
 	}
 	routeConfig.Setup()
 }
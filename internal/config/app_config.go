@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// NewAppConfig decodes the merged configuration into a model.AppConfig and
+// validates it. The returned error lists every problem, one key per line.
+func NewAppConfig(viper *viper.Viper, validate *validator.Validate, translator *ut.UniversalTranslator) (*model.AppConfig, error) {
+	appConfig := new(model.AppConfig)
+	err := viper.Unmarshal(appConfig, func(decoderConfig *mapstructure.DecoderConfig) {
+		decoderConfig.TagName = "json"
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := validate.Struct(appConfig); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return nil, fmt.Errorf("invalid configuration: %w", err)
+		}
+
+		enTranslator, _ := translator.GetTranslator("en")
+		report := []string{"invalid configuration:"}
+		for _, fieldError := range validationErrors {
+			key := strings.TrimPrefix(fieldError.Namespace(), "AppConfig.")
+			report = append(report, fmt.Sprintf("  - %s: %s", key, configErrorMessage(fieldError, enTranslator)))
+		}
+		return nil, errors.New(strings.Join(report, "\n"))
+	}
+
+	return appConfig, nil
+}
+
+// configErrorMessage translates the error, except for duration bounds whose
+// parameters such as 1m the default numeric translations cannot parse.
+func configErrorMessage(fieldError validator.FieldError, trans ut.Translator) string {
+	if fieldError.Type() == reflect.TypeOf(time.Duration(0)) {
+		switch fieldError.Tag() {
+		case "min":
+			return fmt.Sprintf("%s must be at least %s", fieldError.Field(), fieldError.Param())
+		case "max":
+			return fmt.Sprintf("%s must be at most %s", fieldError.Field(), fieldError.Param())
+		}
+	}
+	return fieldError.Translate(trans)
+}
@@ -2,31 +2,99 @@ package config
 
 import (
 	"errors"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
-	"github.com/spf13/viper"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"strings"
 )
 
-func NewFiber(config *viper.Viper) *fiber.App {
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+func NewFiber(config *model.AppConfig, translator *ut.UniversalTranslator) *fiber.App {
 	var app = fiber.New(fiber.Config{
-		AppName:      config.GetString("app.name"),
-		ErrorHandler: NewErrorHandler(),
-		Prefork:      config.GetBool("web.prefork"),
+		AppName:      config.App.Name,
+		ErrorHandler: NewErrorHandler(translator),
+		Prefork:      config.Web.Prefork,
 		JSONEncoder:  json.Marshal,
 		JSONDecoder:  json.Unmarshal,
 	})
 	return app
 }
 
-func NewErrorHandler() fiber.ErrorHandler {
+// NewMetricsFiber builds the app for the metrics.port listener, or returns
+// nil when /metrics stays on the public listener.
+func NewMetricsFiber(config *model.AppConfig, translator *ut.UniversalTranslator) *fiber.App {
+	if config.Metrics.Port == 0 {
+		return nil
+	}
+	return fiber.New(fiber.Config{
+		AppName:               config.App.Name,
+		ErrorHandler:          NewErrorHandler(translator),
+		DisableStartupMessage: true,
+		JSONEncoder:           json.Marshal,
+		JSONDecoder:           json.Unmarshal,
+	})
+}
+
+// NewErrorHandler renders every error as a structured object translated to
+// the locale picked from Accept-Language. Clients that accept
+// application/problem+json receive an RFC 7807 document instead.
+func NewErrorHandler(translator *ut.UniversalTranslator) fiber.ErrorHandler {
 	return func(ctx *fiber.Ctx, err error) error {
-		code := fiber.StatusInternalServerError
-		var e *fiber.Error
-		if errors.As(err, &e) {
-			code = e.Code
+		e := toError(err).Translate(localeTranslator(ctx, translator))
+
+		if strings.Contains(ctx.Get(fiber.HeaderAccept), MIMEApplicationProblemJSON) {
+			return ctx.Status(e.Status).JSON(model.ProblemDetails{
+				Type:     "about:blank",
+				Title:    utils.StatusMessage(e.Status),
+				Status:   e.Status,
+				Detail:   e.Message,
+				Instance: ctx.OriginalURL(),
+				Code:     e.Code,
+				Fields:   e.Fields,
+			}, MIMEApplicationProblemJSON)
 		}
-		return ctx.Status(code).JSON(fiber.Map{
-			"errors": err.Error(),
+
+		return ctx.Status(e.Status).JSON(model.WebResponse[any]{
+			Errors: &model.ErrorResponse{
+				Code:    e.Code,
+				Message: e.Message,
+				Fields:  e.Fields,
+			},
 		})
 	}
 }
+
+// toError maps any error to a domain error. Fiber errors keep their status
+// and message, anything else is hidden behind a generic internal error.
+func toError(err error) *model.Error {
+	var e *model.Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	status := fiber.StatusInternalServerError
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		status = fiberError.Code
+	}
+	message := utils.StatusMessage(status)
+	if fiberError != nil {
+		message = fiberError.Message
+	}
+	return model.NewError(status, errorCode(status), message)
+}
+
+// errorCode derives a machine readable code such as "not_found" from a status.
+func errorCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(utils.StatusMessage(status)), " ", "_")
+}
+
+// localeTranslator picks the translator for the best supported Accept-Language,
+// falling back to English.
+func localeTranslator(ctx *fiber.Ctx, translator *ut.UniversalTranslator) ut.Translator {
+	trans, _ := translator.GetTranslator(ctx.AcceptsLanguages("en", "id"))
+	return trans
+}
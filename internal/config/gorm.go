@@ -1,36 +1,41 @@
 package config
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/glebarez/sqlite"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"net"
+	"net/url"
+	"strconv"
 	"time"
 )
 
-func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
-	username := viper.GetString("database.username")
-	password := viper.GetString("database.password")
-	host := viper.GetString("database.host")
-	port := viper.GetInt("database.port")
-	database := viper.GetString("database.name")
-	idleConnection := viper.GetInt("database.pool.idle")
-	maxConnection := viper.GetInt("database.pool.max")
-	maxLifeTimeConnection := viper.GetInt("database.pool.lifetime")
-
-	mysqlDsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", username, password, host, port, database)
-	//postgresDsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=Asia/Makassar", host, username, password, database, port)
-
-	db, err := gorm.Open(mysql.Open(mysqlDsn), &gorm.Config{
-		Logger: logger.New(&logrusWriter{Logger: log}, logger.Config{
-			SlowThreshold:             time.Second * 5,
-			Colorful:                  false,
-			IgnoreRecordNotFoundError: true,
-			ParameterizedQueries:      true,
-			LogLevel:                  logger.Info,
-		}),
+const sqliteMemory = ":memory:"
+
+const sqlitePragmas = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+func NewDatabase(config *model.AppConfig, log *logrus.Logger) *gorm.DB {
+	idleConnection := config.Database.Pool.Idle
+	maxConnection := config.Database.Pool.Max
+	maxLifeTimeConnection := config.Database.Pool.Lifetime
+
+	dialector, err := NewDialector(config)
+	if err != nil {
+		log.Fatalf("failed to connect database: %v", err.Error())
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: &gormLogger{Log: log, SlowThreshold: time.Second * 5, Params: config.Log.SqlParams},
+		// unique violations surface as gorm.ErrDuplicatedKey on every driver
+		TranslateError: true,
 	})
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err.Error())
@@ -44,19 +49,158 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 	connection.SetMaxOpenConns(maxConnection)
 	connection.SetConnMaxLifetime(time.Second * time.Duration(maxLifeTimeConnection))
 
+	// every connection to an in-memory sqlite database opens a fresh, empty
+	// database, so the pool is pinned to a single connection that never expires
+	if config.Database.Driver == "sqlite" && config.Database.Path == sqliteMemory {
+		connection.SetMaxIdleConns(1)
+		connection.SetMaxOpenConns(1)
+		connection.SetConnMaxLifetime(0)
+	}
+
 	return db
 }
 
-func Migrator(db *gorm.DB, entity ...interface{}) {
-	if err := db.AutoMigrate(entity...); err != nil {
-		panic(fmt.Errorf("failed to connect database: %v", err.Error()))
+// CloseDatabase closes the connection pool once no request can use it anymore.
+func CloseDatabase(db *gorm.DB, log *logrus.Logger) {
+	connection, err := db.DB()
+	if err != nil {
+		log.Errorf("failed to close database: %v", err.Error())
+		return
+	}
+	if err := connection.Close(); err != nil {
+		log.Errorf("failed to close database: %v", err.Error())
+	}
+}
+
+// NewDialector builds the GORM dialector for database.driver, which is one of
+// mysql (the default), postgres or sqlite.
+func NewDialector(config *model.AppConfig) (gorm.Dialector, error) {
+	driverName, dsn, err := DataSource(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch driverName {
+	case "mysql":
+		return mysql.Open(dsn), nil
+	case "pgx":
+		return postgres.Open(dsn), nil
+	default:
+		return sqlite.Open(dsn), nil
+	}
+}
+
+// DataSource returns the database/sql driver name and DSN for database.driver,
+// shared by the GORM connection and the migration runner.
+func DataSource(config *model.AppConfig) (string, string, error) {
+	username := config.Database.Username
+	password := config.Database.Password
+	host := config.Database.Host
+	port := config.Database.Port
+	database := config.Database.Name
+
+	switch driver := config.Database.Driver; driver {
+	case "", "mysql":
+		mysqlDsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", username, password, host, port, database)
+		return "mysql", mysqlDsn, nil
+	case "postgres":
+		query := url.Values{}
+		query.Set("sslmode", valueOrDefault(config.Database.SslMode, "disable"))
+		query.Set("TimeZone", valueOrDefault(config.Database.Timezone, "UTC"))
+		postgresDsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(username, password),
+			Host:     net.JoinHostPort(host, strconv.Itoa(port)),
+			Path:     database,
+			RawQuery: query.Encode(),
+		}
+		return "pgx", postgresDsn.String(), nil
+	case "sqlite":
+		path := valueOrDefault(config.Database.Path, database+".db")
+		// a shared cache lets the migration runner and the application pool
+		// see the same in-memory database
+		if path == sqliteMemory {
+			return sqlite.DriverName, "file::memory:?cache=shared&" + sqlitePragmas, nil
+		}
+		return sqlite.DriverName, path + "?" + sqlitePragmas, nil
+	default:
+		return "", "", fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+func valueOrDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
 	}
+	return value
+}
+
+// gormLogger writes GORM's statement log through the request-scoped entry of
+// the statement context, so every SQL line carries the request id. Queries
+// are logged with placeholders only, unless Params is set, in which case the
+// values are inlined after passing through pkg.RedactValue.
+type gormLogger struct {
+	Log           *logrus.Logger
+	SlowThreshold time.Duration
+	Params        bool
 }
 
-type logrusWriter struct {
-	Logger *logrus.Logger
+func (l *gormLogger) LogMode(logger.LogLevel) logger.Interface {
+	return l
 }
 
-func (l *logrusWriter) Printf(message string, args ...interface{}) {
-	l.Logger.Tracef(message, args)
+func (l *gormLogger) Info(ctx context.Context, message string, args ...interface{}) {
+	pkg.Logger(ctx, l.Log).Infof(message, args...)
+}
+
+func (l *gormLogger) Warn(ctx context.Context, message string, args ...interface{}) {
+	pkg.Logger(ctx, l.Log).Warnf(message, args...)
+}
+
+func (l *gormLogger) Error(ctx context.Context, message string, args ...interface{}) {
+	pkg.Logger(ctx, l.Log).Errorf(message, args...)
+}
+
+// Trace logs failed statements at error and slow ones at warn, so both show
+// up with gorm at warn, while the line for every other statement needs trace.
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	var level logrus.Level
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level = logrus.ErrorLevel
+	case elapsed > l.SlowThreshold:
+		level = logrus.WarnLevel
+	default:
+		level = logrus.TraceLevel
+	}
+	if !l.Log.IsLevelEnabled(level) {
+		return
+	}
+
+	sql, rows := fc()
+	entry := pkg.Logger(ctx, l.Log).WithFields(logrus.Fields{
+		"elapsed_ms": float64(elapsed.Microseconds()) / 1000,
+		"rows":       rows,
+		"sql":        sql,
+	})
+	switch level {
+	case logrus.ErrorLevel:
+		entry.WithError(err).Error("sql failed")
+	case logrus.WarnLevel:
+		entry.Warnf("slow sql >= %v", l.SlowThreshold)
+	default:
+		entry.Trace("sql")
+	}
+}
+
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if !l.Params {
+		return sql, nil
+	}
+	redacted := make([]interface{}, len(params))
+	for i, param := range params {
+		redacted[i] = pkg.RedactValue(param)
+	}
+	return sql, redacted
 }
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+)
+
+// MigrationHealthChecker runs the startup CheckMigration against the
+// configured database, so readiness fails the same way startup would.
+type MigrationHealthChecker struct {
+	Config *model.AppConfig
+}
+
+func (c *MigrationHealthChecker) Name() string {
+	return "migrations"
+}
+
+func (c *MigrationHealthChecker) Check(ctx context.Context) error {
+	// migrate takes no context, so a hanging check is abandoned at the
+	// deadline and closes its connection once it returns
+	result := make(chan error, 1)
+	go func() {
+		result <- CheckMigration(c.Config)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ConfigHealthChecker verifies that a configuration is loaded and that the
+// current snapshot still validates.
+type ConfigHealthChecker struct {
+	Settings *pkg.Settings
+	Validate *validator.Validate
+}
+
+func (c *ConfigHealthChecker) Name() string {
+	return "config"
+}
+
+func (c *ConfigHealthChecker) Check(ctx context.Context) error {
+	config := c.Settings.Get()
+	if config == nil {
+		return fmt.Errorf("configuration is not loaded")
+	}
+	return c.Validate.StructCtx(ctx, config)
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"time"
+)
+
+// StartTokenCleanup deletes the records of expired tokens every interval
+// until ctx is done. Failures are logged by the use case and retried on the
+// next tick.
+func StartTokenCleanup(ctx context.Context, userUseCase *usecase.UserUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = userUseCase.DeleteExpiredTokens(ctx)
+			}
+		}
+	}()
+}
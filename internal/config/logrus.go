@@ -1,15 +1,192 @@
 package config
 
 import (
+	"fmt"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
 )
 
-func NewLogger(viper *viper.Viper) *logrus.Logger {
+var logPackages = []string{model.LogPackageGorm, model.LogPackageRepository, model.LogPackageUseCase, model.LogPackageHttp}
+
+// Logging holds the application logger and one logger per model.LogPackage*
+// name. They share outputs, format and hooks but each has its own level, so
+// GORM can stay at warn while the rest of the application logs debug.
+type Logging struct {
+	Root     *logrus.Logger
+	packages map[string]*logrus.Logger
+	closers  []io.Closer
+}
+
+func NewLogging(config *model.AppConfig) (*Logging, error) {
 	log := logrus.New()
+	logging := &Logging{Root: log, packages: make(map[string]*logrus.Logger)}
+
+	if config.Log.Format == "text" {
+		log.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	} else {
+		log.SetFormatter(&logrus.JSONFormatter{})
+	}
+	log.AddHook(&pkg.TraceHook{})
+	log.AddHook(&pkg.RedactHook{})
+
+	outputs := config.Log.Outputs
+	if len(outputs) == 0 {
+		outputs = []model.LogOutputConfig{{Type: "stdout"}}
+	}
+	writers := make([]io.Writer, 0, len(outputs))
+	for _, output := range outputs {
+		switch output.Type {
+		case "stdout":
+			writers = append(writers, os.Stdout)
+		case "stderr":
+			writers = append(writers, os.Stderr)
+		case "file":
+			file, err := newRotatingFile(output)
+			if err != nil {
+				CloseLogging(logging)
+				return nil, fmt.Errorf("failed to open log file: %w", err)
+			}
+			writers = append(writers, file)
+			logging.closers = append(logging.closers, file)
+		case "syslog":
+			// hooks fire in order, so syslog only ever sees redacted entries
+			hook, closer, err := newSyslogHook(output, config.App.Name)
+			if err != nil {
+				CloseLogging(logging)
+				return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+			}
+			log.AddHook(hook)
+			logging.closers = append(logging.closers, closer)
+		}
+	}
+	// each logger only serializes its own writes, the shared output needs
+	// its own lock so lines from different packages never interleave
+	log.SetOutput(&lockedWriter{writer: io.MultiWriter(writers...)})
+
+	for _, name := range logPackages {
+		logger := logrus.New()
+		logger.Out = log.Out
+		logger.Formatter = log.Formatter
+		logger.Hooks = log.Hooks
+		logging.packages[name] = logger
+	}
+	logging.SetLevels(&config.Log)
+	return logging, nil
+}
+
+// Package returns the logger of one of the model.LogPackage* names.
+func (l *Logging) Package(name string) *logrus.Logger {
+	if logger, ok := l.packages[name]; ok {
+		return logger
+	}
+	return l.Root
+}
+
+// SetLevels applies log.level and the log.packages overrides. Packages
+// without an override follow the application level.
+func (l *Logging) SetLevels(config *model.LogConfig) {
+	level, _ := ParseLogLevel(config.Level)
+	l.Root.SetLevel(level)
+	for name, logger := range l.packages {
+		packageLevel, err := ParseLogLevel(config.Packages[name])
+		if err != nil {
+			packageLevel = level
+		}
+		logger.SetLevel(packageLevel)
+	}
+}
+
+// ParseLogLevel accepts a logrus level name such as info or debug, or the
+// number of the level for configurations written before names were allowed.
+func ParseLogLevel(name string) (logrus.Level, error) {
+	if number, err := strconv.Atoi(name); err == nil {
+		if number < int(logrus.PanicLevel) || number > int(logrus.TraceLevel) {
+			return 0, fmt.Errorf("not a valid logrus level: %q", name)
+		}
+		return logrus.Level(number), nil
+	}
+	return logrus.ParseLevel(name)
+}
 
-	log.SetLevel(logrus.Level(viper.GetInt32("log.level")))
-	log.SetFormatter(&logrus.JSONFormatter{})
+// CloseLogging flushes file backed log output and releases log files and
+// syslog connections before the process exits.
+func CloseLogging(logging *Logging) {
+	// syncing a terminal or pipe fails harmlessly
+	_ = os.Stdout.Sync()
+	_ = os.Stderr.Sync()
+	for _, closer := range logging.closers {
+		_ = closer.Close()
+	}
+}
+
+type lockedWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.writer.Write(p)
+}
+
+// rotatingFile rotates by size through lumberjack and additionally every
+// RotateEvery, so a quiet service still starts a new file each period.
+type rotatingFile struct {
+	*lumberjack.Logger
+	stop chan struct{}
+}
+
+func newRotatingFile(output model.LogOutputConfig) (*rotatingFile, error) {
+	// lumberjack opens the file on the first write, fail at startup instead
+	if err := os.MkdirAll(filepath.Dir(output.Path), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(output.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	_ = file.Close()
+
+	rotating := &rotatingFile{
+		Logger: &lumberjack.Logger{
+			Filename:   output.Path,
+			MaxSize:    output.MaxSize,
+			MaxAge:     output.MaxAge,
+			MaxBackups: output.MaxBackups,
+			Compress:   output.Compress,
+			LocalTime:  true,
+		},
+		stop: make(chan struct{}),
+	}
+	if output.RotateEvery > 0 {
+		go rotating.rotateEvery(output.RotateEvery)
+	}
+	return rotating, nil
+}
+
+func (f *rotatingFile) rotateEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = f.Rotate()
+		case <-f.stop:
+			return
+		}
+	}
+}
 
-	return log
+func (f *rotatingFile) Close() error {
+	close(f.stop)
+	return f.Logger.Close()
 }
@@ -0,0 +1,21 @@
+//go:build !windows && !plan9
+
+package config
+
+import (
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/sirupsen/logrus"
+	logrussyslog "github.com/sirupsen/logrus/hooks/syslog"
+	"io"
+	"log/syslog"
+)
+
+// newSyslogHook sends entries to the local syslog daemon, or to a remote one
+// when network and address are set, at the severity matching their level.
+func newSyslogHook(output model.LogOutputConfig, appName string) (logrus.Hook, io.Closer, error) {
+	hook, err := logrussyslog.NewSyslogHook(output.Network, output.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, valueOrDefault(output.Tag, appName))
+	if err != nil {
+		return nil, nil, err
+	}
+	return hook, hook.Writer, nil
+}
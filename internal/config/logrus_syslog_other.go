@@ -0,0 +1,14 @@
+//go:build windows || plan9
+
+package config
+
+import (
+	"errors"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+func newSyslogHook(output model.LogOutputConfig, appName string) (logrus.Hook, io.Closer, error) {
+	return nil, nil, errors.New("syslog is not supported on this platform")
+}
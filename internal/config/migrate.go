@@ -0,0 +1,140 @@
+package config
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/manikandareas/go-clean-architecture/db/migrations"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"io/fs"
+	"os"
+)
+
+// NewMigrate pairs the embedded migrations for database.driver with a
+// dedicated connection to the database. Closing the returned instance closes
+// that connection.
+func NewMigrate(config *model.AppConfig) (*migrate.Migrate, error) {
+	driverName, dsn, err := DataSource(config)
+	if err != nil {
+		return nil, err
+	}
+	// migration files may hold several statements, which the MySQL driver
+	// only accepts when asked to
+	if driverName == "mysql" {
+		dsn += "&multiStatements=true"
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	var instance database.Driver
+	switch driverName {
+	case "mysql":
+		instance, err = mysql.WithInstance(db, &mysql.Config{})
+	case "pgx":
+		instance, err = pgx.WithInstance(db, &pgx.Config{})
+	default:
+		// Drop turns foreign keys off on the connection it drops the tables with
+		db.SetMaxOpenConns(1)
+		instance, err = newSqliteMigration(db)
+	}
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	migrationSource, err := newMigrationSource(driverName)
+	if err != nil {
+		_ = instance.Close()
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("iofs", migrationSource, driverName, instance)
+}
+
+// LatestMigration returns the newest version embedded for database.driver,
+// which is the version the application code expects the schema to be at.
+func LatestMigration(config *model.AppConfig) (uint, error) {
+	driverName, _, err := DataSource(config)
+	if err != nil {
+		return 0, err
+	}
+
+	migrationSource, err := newMigrationSource(driverName)
+	if err != nil {
+		return 0, err
+	}
+	defer migrationSource.Close()
+
+	version, err := migrationSource.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := migrationSource.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// MigrateUp applies every pending migration.
+func MigrateUp(config *model.AppConfig) error {
+	m, err := NewMigrate(config)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// CheckMigration returns an error when the schema is dirty or older than the
+// latest embedded migration, in which case the application must not serve.
+func CheckMigration(config *model.AppConfig) error {
+	latest, err := LatestMigration(config)
+	if err != nil {
+		return err
+	}
+
+	m, err := NewMigrate(config)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("database schema is dirty at version %d, repair it and run `migrate force <version>`", version)
+	}
+	if version < latest {
+		return fmt.Errorf("database schema is at version %d but %d is required, run `migrate up`", version, latest)
+	}
+	return nil
+}
+
+func newMigrationSource(driverName string) (source.Driver, error) {
+	directory := map[string]string{"mysql": "mysql", "pgx": "postgres", "sqlite": "sqlite"}[driverName]
+	if _, err := fs.Stat(migrations.FS, directory); err != nil {
+		return nil, fmt.Errorf("no migrations for database driver %q", driverName)
+	}
+	return iofs.New(migrations.FS, directory)
+}
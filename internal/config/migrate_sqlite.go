@@ -0,0 +1,160 @@
+package config
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4/database"
+	"io"
+	"sync/atomic"
+)
+
+const sqliteMigrationsTable = "schema_migrations"
+
+// sqliteMigration runs migrations over a connection of the pure Go SQLite
+// driver GORM uses. golang-migrate's own sqlite driver links modernc.org/sqlite,
+// which registers the same database/sql name and panics next to it, and its
+// sqlite3 driver needs cgo.
+type sqliteMigration struct {
+	db       *sql.DB
+	isLocked atomic.Bool
+}
+
+func newSqliteMigration(db *sql.DB) (database.Driver, error) {
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (version uint64, dirty bool);
+CREATE UNIQUE INDEX IF NOT EXISTS version_unique ON %s (version);`, sqliteMigrationsTable, sqliteMigrationsTable)
+	if _, err := db.Exec(query); err != nil {
+		return nil, &database.Error{OrigErr: err, Query: []byte(query)}
+	}
+	return &sqliteMigration{db: db}, nil
+}
+
+// Open is only needed for migration URLs, the driver is always built from
+// the connection of the application.
+func (m *sqliteMigration) Open(url string) (database.Driver, error) {
+	return nil, errors.New("sqlite migrations are opened with a connection")
+}
+
+func (m *sqliteMigration) Close() error {
+	return m.db.Close()
+}
+
+func (m *sqliteMigration) Lock() error {
+	if !m.isLocked.CompareAndSwap(false, true) {
+		return database.ErrLocked
+	}
+	return nil
+}
+
+func (m *sqliteMigration) Unlock() error {
+	if !m.isLocked.CompareAndSwap(true, false) {
+		return database.ErrNotLocked
+	}
+	return nil
+}
+
+// Run applies a migration file in a transaction, so a failing statement
+// leaves no part of it behind.
+func (m *sqliteMigration) Run(migration io.Reader) error {
+	content, err := io.ReadAll(migration)
+	if err != nil {
+		return err
+	}
+	query := string(content)
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return &database.Error{OrigErr: err, Err: "transaction start failed"}
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query); err != nil {
+		return &database.Error{OrigErr: err, Query: content}
+	}
+	if err := tx.Commit(); err != nil {
+		return &database.Error{OrigErr: err, Err: "transaction commit failed"}
+	}
+	return nil
+}
+
+func (m *sqliteMigration) SetVersion(version int, dirty bool) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return &database.Error{OrigErr: err, Err: "transaction start failed"}
+	}
+	defer tx.Rollback()
+
+	query := "DELETE FROM " + sqliteMigrationsTable
+	if _, err := tx.Exec(query); err != nil {
+		return &database.Error{OrigErr: err, Query: []byte(query)}
+	}
+
+	// a failed down migration of the first version is recorded as a dirty
+	// nil version, otherwise it would look like an empty schema
+	if version >= 0 || (version == database.NilVersion && dirty) {
+		query := "INSERT INTO " + sqliteMigrationsTable + " (version, dirty) VALUES (?, ?)"
+		if _, err := tx.Exec(query, version, dirty); err != nil {
+			return &database.Error{OrigErr: err, Query: []byte(query)}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &database.Error{OrigErr: err, Err: "transaction commit failed"}
+	}
+	return nil
+}
+
+func (m *sqliteMigration) Version() (int, bool, error) {
+	var version int
+	var dirty bool
+	query := "SELECT version, dirty FROM " + sqliteMigrationsTable + " LIMIT 1"
+	err := m.db.QueryRow(query).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.NilVersion, false, nil
+	}
+	if err != nil {
+		return 0, false, &database.Error{OrigErr: err, Query: []byte(query)}
+	}
+	return version, dirty, nil
+}
+
+// Drop removes every table, including the migrations table.
+func (m *sqliteMigration) Drop() error {
+	query := "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'"
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return &database.Error{OrigErr: err, Query: []byte(query)}
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return &database.Error{OrigErr: err, Query: []byte(query)}
+	}
+
+	// tables may reference each other, so foreign keys are off while dropping
+	if _, err := m.db.Exec("PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer m.db.Exec("PRAGMA foreign_keys = ON")
+	for _, table := range tables {
+		query := fmt.Sprintf("DROP TABLE %q", table)
+		if _, err := m.db.Exec(query); err != nil {
+			return &database.Error{OrigErr: err, Query: []byte(query)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/spf13/viper"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// WatchConfig reloads the configuration until ctx is done whenever the base
+// config file or the file of its profile changes, re-reading both in the same
+// order as at startup. Only the log levels, rate limits, token lifetimes and
+// feature flags are applied at runtime; everything else still needs a restart.
+func WatchConfig(ctx context.Context, viper *viper.Viper, profile string, validate *validator.Validate, translator *ut.UniversalTranslator, settings *pkg.Settings, logging *Logging) {
+	log := logging.Root
+	path, err := filepath.Abs(viper.ConfigFileUsed())
+	if err != nil {
+		log.WithError(err).Error("failed to watch the configuration")
+		return
+	}
+	files := []string{path}
+	if profile = resolveProfile(profile); profile != "" {
+		files = append(files, profilePath(path, profile))
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.WithError(err).Error("failed to watch the configuration")
+		return
+	}
+	// the directory is watched rather than the files, editors and mounted
+	// config maps replace a file instead of writing to it
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		log.WithError(err).Error("failed to watch the configuration")
+		return
+	}
+
+	targets := realPaths(files)
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				changed := event.Op&(fsnotify.Write|fsnotify.Create) != 0 && slices.Contains(files, filepath.Clean(event.Name))
+				// a swapped symlink changes the file without an event for its name
+				if current := realPaths(files); !slices.Equal(current, targets) {
+					targets = current
+					changed = true
+				}
+				if changed {
+					ReloadConfig(path, profile, validate, translator, settings, logging)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.WithError(err).Error("failed to watch the configuration")
+			}
+		}
+	}()
+}
+
+// realPaths resolves the symlinks of files, keeping a file that does not
+// resolve as it is.
+func realPaths(files []string) []string {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		if path, err := filepath.EvalSymlinks(file); err == nil {
+			paths[i] = path
+		} else {
+			paths[i] = file
+		}
+	}
+	return paths
+}
+
+// ReloadConfig re-reads files and environment the same way startup does and
+// applies the reloadable settings once the whole configuration validates.
+func ReloadConfig(path string, profile string, validate *validator.Validate, translator *ut.UniversalTranslator, settings *pkg.Settings, logging *Logging) {
+	log := logging.Root
+	reloaded, err := loadViper(path, profile)
+	if err != nil {
+		log.WithError(err).Error("rejected configuration reload")
+		return
+	}
+	next, err := NewAppConfig(reloaded, validate, translator)
+	if err != nil {
+		log.WithError(err).Error("rejected configuration reload")
+		return
+	}
+
+	current := settings.Get()
+	updated, changes := mergeRuntimeSettings(current, next)
+	if !reflect.DeepEqual(*updated, *next) {
+		log.Warn("configuration changes outside the reloadable settings take effect after a restart")
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	logging.SetLevels(&updated.Log)
+	settings.Set(updated)
+	// logged as a warning so the audit line survives a lowered log level
+	log.WithField("changes", changes).Warn("runtime settings reloaded")
+}
+
+// mergeRuntimeSettings copies the reloadable settings of next onto a copy of
+// current and describes every value that changed.
+func mergeRuntimeSettings(current *model.AppConfig, next *model.AppConfig) (*model.AppConfig, []string) {
+	var changes []string
+	diff := func(key string, before any, after any) {
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, fmt.Sprintf("%s: %v -> %v", key, before, after))
+		}
+	}
+
+	diff("log.level", current.Log.Level, next.Log.Level)
+	for _, name := range logPackages {
+		diff("log.packages."+name, current.Log.Packages[name], next.Log.Packages[name])
+	}
+	diff("web.rateLimit.max", current.Web.RateLimit.Max, next.Web.RateLimit.Max)
+	diff("web.rateLimit.expiration", current.Web.RateLimit.Expiration, next.Web.RateLimit.Expiration)
+	diff("jwt.accessTokenTtl", current.Jwt.AccessTokenTtl, next.Jwt.AccessTokenTtl)
+	diff("jwt.refreshTokenTtl", current.Jwt.RefreshTokenTtl, next.Jwt.RefreshTokenTtl)
+
+	features := make([]string, 0, len(current.Features)+len(next.Features))
+	for name := range current.Features {
+		features = append(features, name)
+	}
+	for name := range next.Features {
+		if _, ok := current.Features[name]; !ok {
+			features = append(features, name)
+		}
+	}
+	sort.Strings(features)
+	for _, name := range features {
+		diff("features."+name, current.FeatureEnabled(name), next.FeatureEnabled(name))
+	}
+
+	updated := *current
+	updated.Log.Level = next.Log.Level
+	updated.Log.Packages = next.Log.Packages
+	updated.Web.RateLimit = next.Web.RateLimit
+	updated.Jwt.AccessTokenTtl = next.Jwt.AccessTokenTtl
+	updated.Jwt.RefreshTokenTtl = next.Jwt.RefreshTokenTtl
+	updated.Features = next.Features
+	return &updated, changes
+}
@@ -0,0 +1,74 @@
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/manikandareas/go-clean-architecture/internal/config"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const profileTemplate = `{
+  "log": {"level": "error"},
+  "web": {"rateLimit": {"max": %d, "expiration": "1m"}},
+  "jwt": {
+    "accessToken": "access-token-secret-for-unit-tests",
+    "refreshToken": "refresh-token-secret-for-unit-tests"
+  },
+  "pagination": {"cursorSecret": "cursor-secret-for-the-unit-tests"}
+}`
+
+func writeProfile(t *testing.T, path string, rateLimit int) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(profileTemplate, rateLimit)), 0o600); err != nil {
+		t.Fatalf("writing %s failed: %v", path, err)
+	}
+}
+
+func TestWatchConfigProfile(t *testing.T) {
+	base, err := os.ReadFile("../../config.json")
+	if err != nil {
+		t.Fatalf("reading the base config failed: %v", err)
+	}
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(basePath, base, 0o600); err != nil {
+		t.Fatalf("writing the base config failed: %v", err)
+	}
+	profilePath := filepath.Join(dir, "config.test.json")
+	writeProfile(t, profilePath, 30)
+
+	viper := config.NewViper(basePath, "test")
+	translator := config.NewTranslator()
+	validate := config.NewValidator(viper, translator)
+	appConfig, err := config.NewAppConfig(viper, validate, translator)
+	if err != nil {
+		t.Fatalf("NewAppConfig failed: %v", err)
+	}
+	logging, err := config.NewLogging(appConfig)
+	if err != nil {
+		t.Fatalf("NewLogging failed: %v", err)
+	}
+	defer config.CloseLogging(logging)
+	settings := pkg.NewSettings(appConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config.WatchConfig(ctx, viper, "test", validate, translator, settings, logging)
+
+	writeProfile(t, profilePath, 40)
+	deadline := time.Now().Add(5 * time.Second)
+	for settings.Get().Web.RateLimit.Max != 40 {
+		if time.Now().After(deadline) {
+			t.Fatalf("rate limit is %d after editing the profile, want 40", settings.Get().Web.RateLimit.Max)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// the profile still overrides the base after the reload
+	if level := settings.Get().Log.Level; level != "error" {
+		t.Fatalf("log level is %s after the reload, want the profile's error", level)
+	}
+}
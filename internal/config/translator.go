@@ -0,0 +1,61 @@
+package config
+
+import (
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+)
+
+// messages translates the error codes and custom field rules that are not
+// covered by the validator's own translations.
+var messages = map[string]map[string]string{
+	"en": {
+		"validation_failed":     "Request validation failed",
+		"bad_request":           "Bad Request",
+		"unauthorized":          "Unauthorized",
+		"forbidden":             "Forbidden",
+		"not_found":             "Not Found",
+		"method_not_allowed":    "Method Not Allowed",
+		"conflict":              "Conflict",
+		"unprocessable_entity":  "Unprocessable Entity",
+		"too_many_requests":     "Too Many Requests",
+		"internal_server_error": "Internal Server Error",
+		"service_unavailable":   "Service Unavailable",
+		"field_exists":          "{0} must reference an existing record",
+		"field_cursor":          "{0} must be a cursor returned by a previous page",
+		"field_token":           "{0} must be a valid token",
+		"field_oneof":           "{0} must be one of [{1}]",
+	},
+	"id": {
+		"validation_failed":     "Validasi permintaan gagal",
+		"bad_request":           "Permintaan tidak valid",
+		"unauthorized":          "Tidak terautentikasi",
+		"forbidden":             "Akses ditolak",
+		"not_found":             "Data tidak ditemukan",
+		"method_not_allowed":    "Metode tidak diizinkan",
+		"conflict":              "Data bertentangan dengan data yang sudah ada",
+		"unprocessable_entity":  "Permintaan tidak dapat diproses",
+		"too_many_requests":     "Terlalu banyak permintaan",
+		"internal_server_error": "Terjadi kesalahan pada server",
+		"service_unavailable":   "Layanan tidak tersedia",
+		"field_exists":          "{0} harus merujuk ke data yang ada",
+		"field_cursor":          "{0} harus berupa cursor dari halaman sebelumnya",
+		"field_token":           "{0} harus berupa token yang valid",
+		"field_oneof":           "{0} harus berupa salah satu dari [{1}]",
+	},
+}
+
+// NewTranslator supports English, the fallback, and Indonesian.
+func NewTranslator() *ut.UniversalTranslator {
+	translator := ut.New(en.New(), en.New(), id.New())
+
+	for locale, catalog := range messages {
+		trans, _ := translator.GetTranslator(locale)
+		for key, text := range catalog {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return translator
+}
@@ -1,10 +1,59 @@
 package config
 
 import (
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	idtranslations "github.com/go-playground/validator/v10/translations/id"
 	"github.com/spf13/viper"
+	"reflect"
+	"strings"
 )
 
-func NewValidator(viper *viper.Viper) *validator.Validate {
-	return validator.New()
+func NewValidator(viper *viper.Viper, translator *ut.UniversalTranslator) *validator.Validate {
+	validate := validator.New()
+	// report fields by their json name so errors match the request body
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	enTranslator, _ := translator.GetTranslator("en")
+	if err := entranslations.RegisterDefaultTranslations(validate, enTranslator); err != nil {
+		panic(err)
+	}
+	idTranslator, _ := translator.GetTranslator("id")
+	if err := idtranslations.RegisterDefaultTranslations(validate, idTranslator); err != nil {
+		panic(err)
+	}
+
+	// rules used by the request models that the default sets do not cover
+	registerTranslation(validate, enTranslator, "required_with", "{0} is required")
+	registerTranslation(validate, idTranslator, "required_with", "{0} wajib diisi")
+
+	// rules used by the configuration
+	if err := validate.RegisterValidation("loglevel", func(fieldLevel validator.FieldLevel) bool {
+		_, err := ParseLogLevel(fieldLevel.Field().String())
+		return err == nil
+	}); err != nil {
+		panic(err)
+	}
+	registerTranslation(validate, enTranslator, "loglevel", "{0} must be a log level such as info or debug")
+	registerTranslation(validate, idTranslator, "loglevel", "{0} harus berupa level log seperti info atau debug")
+	return validate
+}
+
+func registerTranslation(validate *validator.Validate, trans ut.Translator, tag string, text string) {
+	err := validate.RegisterTranslation(tag, trans, func(trans ut.Translator) error {
+		return trans.Add(tag, text, true)
+	}, func(trans ut.Translator, fieldError validator.FieldError) string {
+		message, _ := trans.T(tag, fieldError.Field())
+		return message
+	})
+	if err != nil {
+		panic(err)
+	}
 }
@@ -3,19 +3,108 @@ package config
 import (
 	"fmt"
 	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
-func NewViper() *viper.Viper {
+const envPrefix = "APP"
+
+// NewViper reads the base configuration, merges the selected profile file
+// over it and lets the environment override single keys:
+//
+//   - path picks the base file, otherwise config.json is searched for in the
+//     working directory and two levels up.
+//   - profile, or APP_PROFILE when it is empty, merges config.<profile>.json
+//     from the same directory as the base file.
+//   - APP_<KEY> overrides a key, with dots written as underscores, e.g.
+//     APP_DATABASE_PASSWORD or APP_JWT_ACCESSTOKEN.
+//   - APP_<KEY>_FILE reads the value from a file instead, for Docker secrets.
+func NewViper(path string, profile string) *viper.Viper {
+	config, err := loadViper(path, profile)
+	if err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// loadViper builds a fresh viper from the files and environment; it is also
+// used to re-read the configuration on reload.
+func loadViper(path string, profile string) (*viper.Viper, error) {
 	config := viper.New()
 
-	config.SetConfigName("config")
 	config.SetConfigType("json")
-	config.AddConfigPath("./../..")
-	config.AddConfigPath("./")
+	if path != "" {
+		config.SetConfigFile(path)
+	} else {
+		config.SetConfigName("config")
+		config.AddConfigPath("./../..")
+		config.AddConfigPath("./")
+	}
 	err := config.ReadInConfig()
 	if err != nil {
-		panic(fmt.Errorf("Fatal error config file: %w \n", err))
+		return nil, fmt.Errorf("Fatal error config file: %w \n", err)
 	}
 
-	return config
+	if profile = resolveProfile(profile); profile != "" {
+		if err := mergeProfile(config, profile); err != nil {
+			return nil, fmt.Errorf("Fatal error config profile %s: %w \n", profile, err)
+		}
+	}
+
+	config.SetEnvPrefix(envPrefix)
+	config.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	config.AutomaticEnv()
+
+	if err := readSecretFiles(config); err != nil {
+		return nil, fmt.Errorf("Fatal error config secret: %w \n", err)
+	}
+
+	return config, nil
+}
+
+// resolveProfile falls back to APP_PROFILE when no profile is given.
+func resolveProfile(profile string) string {
+	if profile == "" {
+		return os.Getenv(envPrefix + "_PROFILE")
+	}
+	return profile
+}
+
+// profilePath is config.<profile>.json next to the base file.
+func profilePath(base string, profile string) string {
+	extension := filepath.Ext(base)
+	return strings.TrimSuffix(base, extension) + "." + profile + extension
+}
+
+func mergeProfile(config *viper.Viper, profile string) error {
+	file, err := os.Open(profilePath(config.ConfigFileUsed(), profile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return config.MergeConfig(file)
+}
+
+// readSecretFiles resolves APP_<KEY>_FILE for every known key. Setting both
+// the variable and its _FILE form is ambiguous and therefore rejected.
+func readSecretFiles(config *viper.Viper) error {
+	for _, key := range config.AllKeys() {
+		name := envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		path, ok := os.LookupEnv(name + "_FILE")
+		if !ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			return fmt.Errorf("both %s and %s_FILE are set", name, name)
+		}
+
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		config.Set(key, strings.TrimRight(string(value), "\r\n"))
+	}
+	return nil
 }
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+	"math"
+)
+
+type AuthorController struct {
+	UseCase *usecase.AuthorUseCase
+	Log     *logrus.Logger
+}
+
+func NewAuthorController(useCase *usecase.AuthorUseCase, log *logrus.Logger) *AuthorController {
+	return &AuthorController{
+		UseCase: useCase,
+		Log:     log,
+	}
+}
+
+func (c *AuthorController) FindAll(ctx *fiber.Ctx) error {
+	request := &model.SearchAuthorRequest{
+		Name: ctx.Query("name", ""),
+		Page: ctx.QueryInt("page", 1),
+		Size: ctx.QueryInt("size", 10),
+	}
+
+	responses, total, err := c.UseCase.Search(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to search authors")
+		return err
+	}
+
+	paging := &model.PageMetadata{
+		OffsetPage: &model.OffsetPage{
+			Page:      request.Page,
+			TotalItem: total,
+			TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
+		},
+		Size: request.Size,
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.AuthorResponse]{
+		Data:   responses,
+		Paging: paging,
+	})
+}
+
+func (c *AuthorController) Create(ctx *fiber.Ctx) error {
+	request := new(model.AuthorRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to parse request body")
+		return fiber.ErrBadRequest
+	}
+
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to create author")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": response})
+}
+
+func (c *AuthorController) Get(ctx *fiber.Ctx) error {
+	request := &model.GetAuthorRequest{
+		ID: ctx.Params("authorId"),
+	}
+
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to get author")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": response})
+}
+
+func (c *AuthorController) Update(ctx *fiber.Ctx) error {
+	request := new(model.UpdateAuthorRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to parse request body")
+		return fiber.ErrBadRequest
+	}
+	request.ID = ctx.Params("authorId")
+
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to update author")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": response})
+}
+
+func (c *AuthorController) Delete(ctx *fiber.Ctx) error {
+	request := &model.DeleteAuthorRequest{
+		ID: ctx.Params("authorId"),
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to delete author")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": true})
+}
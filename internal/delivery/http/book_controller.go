@@ -2,9 +2,13 @@ package http
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/manikandareas/go-clean-architecture/internal/delivery/http/middleware"
 	"github.com/manikandareas/go-clean-architecture/internal/model"
 	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"github.com/manikandareas/go-clean-architecture/pkg"
 	"github.com/sirupsen/logrus"
+	"math"
+	"strings"
 )
 
 type BookController struct {
@@ -19,27 +23,126 @@ func NewBookController(useCase *usecase.BookUseCase, log *logrus.Logger) *BookCo
 	}
 }
 func (c *BookController) FindAll(ctx *fiber.Ctx) error {
-	response, err := c.UseCase.List(ctx.UserContext())
+	request := &model.SearchBookRequest{
+		Title:         ctx.Query("title", ""),
+		AuthorId:      ctx.Query("author_id", ""),
+		Sort:          ctx.Query("sort", ""),
+		Page:          ctx.QueryInt("page", 1),
+		Size:          ctx.QueryInt("size", 10),
+		IncludeAuthor: includes(ctx, "author"),
+	}
+
+	// Passing a cursor or a limit switches the listing to keyset pagination,
+	// both next and prev cursors are sent back through the after parameter.
+	if request.After = ctx.Query("after", ""); request.After != "" || ctx.Query("limit") != "" {
+		request.Size = ctx.QueryInt("limit", 10)
 
+		responses, paging, err := c.UseCase.SearchByCursor(ctx.UserContext(), request)
+		if err != nil {
+			pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to search books")
+			return err
+		}
+		return ctx.JSON(model.WebResponse[[]model.BookResponse]{
+			Data:   responses,
+			Paging: paging,
+		})
+	}
+
+	responses, total, err := c.UseCase.Search(ctx.UserContext(), request)
 	if err != nil {
-		c.Log.WithError(err).Error("failed to get books")
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to search books")
 		return err
 	}
-	return ctx.JSON(fiber.Map{"data": response})
+
+	paging := &model.PageMetadata{
+		OffsetPage: &model.OffsetPage{
+			Page:      request.Page,
+			TotalItem: total,
+			TotalPage: int64(math.Ceil(float64(total) / float64(request.Size))),
+		},
+		Size: request.Size,
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.BookResponse]{
+		Data:   responses,
+		Paging: paging,
+	})
 }
 
 func (c *BookController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
 	request := new(model.BookRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to parse request body")
+		return fiber.ErrBadRequest
+	}
+	request.UserId = auth.ID
 
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to create book")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": response})
+}
+
+func (c *BookController) Get(ctx *fiber.Ctx) error {
+	request := &model.GetBookRequest{
+		ID:            ctx.Params("bookId"),
+		IncludeAuthor: includes(ctx, "author"),
+	}
+
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to get book")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": response})
+}
+
+func (c *BookController) Update(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.UpdateBookRequest)
 	if err := ctx.BodyParser(request); err != nil {
-		c.Log.WithError(err).Error("failed to parse request body")
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to parse request body")
 		return fiber.ErrBadRequest
 	}
+	request.ID = ctx.Params("bookId")
+	request.UserId = auth.ID
+	request.IsAdmin = auth.HasRole(model.RoleAdmin)
 
-	response, err := c.UseCase.Create(ctx.Context(), request)
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
 	if err != nil {
-		c.Log.WithError(err).Error("failed to create book")
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to update book")
 		return err
 	}
 	return ctx.JSON(fiber.Map{"data": response})
 }
+
+func (c *BookController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.DeleteBookRequest{
+		ID:      ctx.Params("bookId"),
+		UserId:  auth.ID,
+		IsAdmin: auth.HasRole(model.RoleAdmin),
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to delete book")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": true})
+}
+
+// includes reports whether the comma separated include query names relation.
+func includes(ctx *fiber.Ctx, relation string) bool {
+	for _, include := range strings.Split(ctx.Query("include"), ",") {
+		if strings.TrimSpace(include) == relation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+type HealthController struct {
+	UseCase *usecase.HealthUseCase
+	Log     *logrus.Logger
+}
+
+func NewHealthController(useCase *usecase.HealthUseCase, log *logrus.Logger) *HealthController {
+	return &HealthController{
+		UseCase: useCase,
+		Log:     log,
+	}
+}
+
+func (c *HealthController) Live(ctx *fiber.Ctx) error {
+	return ctx.JSON(fiber.Map{"data": c.UseCase.Live()})
+}
+
+func (c *HealthController) Ready(ctx *fiber.Ctx) error {
+	response := c.UseCase.Ready(ctx.UserContext())
+	if response.Status != model.HealthStatusUp {
+		ctx.Status(fiber.StatusServiceUnavailable)
+	}
+	return ctx.JSON(fiber.Map{"data": response})
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// NewAccessLog writes one line per request through the request-scoped
+// logger, so it carries the request id next to the route template, status,
+// latency, response size, client address and authenticated user.
+func NewAccessLog(log *logrus.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+		self := ctx.Route()
+
+		status := next(ctx)
+		fields := logrus.Fields{
+			"route":      routeTemplate(ctx, self),
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      len(ctx.Response().Body()),
+			"client_ip":  ctx.IP(),
+		}
+		if auth, ok := ctx.Locals("auth").(*model.Auth); ok {
+			fields["user_id"] = auth.ID
+		}
+		pkg.Logger(ctx.UserContext(), log).WithFields(fields).Info("access")
+		return nil
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/manikandareas/go-clean-architecture/internal/model"
 	"github.com/manikandareas/go-clean-architecture/internal/usecase"
 	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
 	"strings"
 )
 
@@ -16,34 +17,27 @@ func NewAuth(userUseCase *usecase.UserUseCase) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		authorizationHeader := ctx.Get("Authorization")
 		if !strings.Contains(authorizationHeader, "Bearer") {
-			userUseCase.Log.Warnf("Invalid token")
+			pkg.Logger(ctx.UserContext(), userUseCase.Log).Warnf("Invalid token")
 			return fiber.ErrUnauthorized
 		}
 
 		tokenString := strings.Replace(authorizationHeader, "Bearer ", "", -1)
-		userUseCase.Log.Debugf("Authorization : %s", tokenString)
 		// Decode token to extract information user
-		claims, err := userUseCase.JwtService.DecodeJwtToken(tokenString, pkg.ACCESS_TOKEN_KEY)
+		auth, err := userUseCase.JwtService.DecodeAuth(tokenString, pkg.ACCESS_TOKEN_KEY)
 		if err != nil {
-			userUseCase.Log.Warnf("Failed to decode token : %+v", err)
+			pkg.Logger(ctx.UserContext(), userUseCase.Log).Warnf("Failed to decode token : %+v", err)
 			return fiber.ErrUnauthorized
 		}
-
-		user := claims["User"].(map[string]interface{})
-		auth := &model.Auth{
-			ID:    user["user_id"].(string),
-			Email: user["email"].(string),
-			Name:  user["name"].(string),
-		}
-		// search user from db and count, return err if count < 1
-		err = userUseCase.Verify(ctx.Context(), auth)
+		// search user from db and count, reject revoked tokens
+		err = userUseCase.Verify(ctx.UserContext(), auth)
 		if err != nil {
-			userUseCase.Log.Warnf("Failed find user by id : %+v", err)
+			pkg.Logger(ctx.UserContext(), userUseCase.Log).Warnf("Failed find user by id : %+v", err)
 			return fiber.ErrUnauthorized
 		}
-		userUseCase.Log.Debugf("User : %+v", auth.ID)
+		pkg.Logger(ctx.UserContext(), userUseCase.Log).Debugf("User : %+v", auth.ID)
 		// inject auth information to local var
 		ctx.Locals("auth", auth)
+		withUser(ctx, userUseCase.Log, auth)
 		return ctx.Next()
 	}
 }
@@ -56,29 +50,51 @@ func NewRefreshToken(userUseCase *usecase.UserUseCase) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		authorizationHeader := ctx.Get("Authorization")
 		if !strings.Contains(authorizationHeader, "Refresh") {
-			userUseCase.Log.Warnf("Invalid token")
+			pkg.Logger(ctx.UserContext(), userUseCase.Log).Warnf("Invalid token")
 			return fiber.ErrUnauthorized
 		}
 		tokenString := strings.Replace(authorizationHeader, "Refresh ", "", -1)
-		userUseCase.Log.Debugf("Refresh Token : %s", tokenString)
 
 		// Decode token to extract information user
-		claims, err := userUseCase.JwtService.DecodeJwtToken(tokenString, pkg.REFRESH_TOKEN_KEY)
+		auth, err := userUseCase.JwtService.DecodeAuth(tokenString, pkg.REFRESH_TOKEN_KEY)
 		if err != nil {
-			userUseCase.Log.Warnf("Failed to decode token : %+v", err)
+			pkg.Logger(ctx.UserContext(), userUseCase.Log).Warnf("Failed to decode token : %+v", err)
 			return fiber.ErrUnauthorized
 		}
-		user := claims["User"].(map[string]interface{})
-		auth := &model.Auth{
-			ID:    user["user_id"].(string),
-			Email: user["email"].(string),
-			Name:  user["name"].(string),
+		// a logged out refresh token must not mint new access tokens
+		if err := userUseCase.Verify(ctx.UserContext(), auth); err != nil {
+			pkg.Logger(ctx.UserContext(), userUseCase.Log).Warnf("Failed find user by id : %+v", err)
+			return fiber.ErrUnauthorized
 		}
 		ctx.Locals("auth", auth)
+		ctx.Locals("token", tokenString)
+		withUser(ctx, userUseCase.Log, auth)
 		return ctx.Next()
 	}
 }
 
+// withUser adds the authenticated user to the request logger.
+func withUser(ctx *fiber.Ctx, log *logrus.Logger, auth *model.Auth) {
+	entry := pkg.Logger(ctx.UserContext(), log).WithField("user_id", auth.ID)
+	ctx.SetUserContext(pkg.ContextWithLogger(ctx.UserContext(), entry))
+}
+
 func GetUser(ctx *fiber.Ctx) *model.Auth {
 	return ctx.Locals("auth").(*model.Auth)
 }
+
+// GetToken returns the raw token the request authenticated with.
+func GetToken(ctx *fiber.Ctx) string {
+	return ctx.Locals("token").(string)
+}
+
+// RequirePermission only lets requests through whose token grants the
+// permission. It must run after NewAuth.
+func RequirePermission(permission string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if !GetUser(ctx).HasPermission(permission) {
+			return fiber.ErrForbidden
+		}
+		return ctx.Next()
+	}
+}
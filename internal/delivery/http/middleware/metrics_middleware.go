@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"strconv"
+	"time"
+)
+
+// NewMetrics counts and times every request by its route template, so
+// /api/books/:bookId is one series no matter which book was asked for.
+func NewMetrics(metrics *pkg.Metrics) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+		self := ctx.Route()
+
+		status := strconv.Itoa(next(ctx))
+		// fiber reuses the method buffer, label values must own their memory
+		method := utils.CopyString(ctx.Method())
+		route := routeTemplate(ctx, self)
+
+		metrics.HttpRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HttpRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
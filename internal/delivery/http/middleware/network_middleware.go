@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/netip"
+)
+
+// NewAllowNetworks rejects clients whose IP lies outside of networks, given
+// as CIDRs the configuration validation has already checked. An empty list
+// allows every client.
+func NewAllowNetworks(networks []string) fiber.Handler {
+	prefixes := make([]netip.Prefix, 0, len(networks))
+	for _, network := range networks {
+		prefixes = append(prefixes, netip.MustParsePrefix(network).Masked())
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		if len(prefixes) == 0 {
+			return ctx.Next()
+		}
+		if ip, err := netip.ParseAddr(ctx.IP()); err == nil {
+			for _, prefix := range prefixes {
+				if prefix.Contains(ip.Unmap()) {
+					return ctx.Next()
+				}
+			}
+		}
+		return fiber.ErrForbidden
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+// next runs the rest of the chain and, on an error, the error handler right
+// away, so the status a wrapping middleware records is the one sent.
+func next(ctx *fiber.Ctx) int {
+	if err := ctx.Next(); err != nil {
+		if err := ctx.App().ErrorHandler(ctx, err); err != nil {
+			_ = ctx.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+	return ctx.Response().StatusCode()
+}
+
+// routeTemplate returns the template of the route that handled the request
+// once next returned. Requests that matched no route end on the calling
+// middleware, self, and are reported as unmatched.
+func routeTemplate(ctx *fiber.Ctx, self *fiber.Route) string {
+	if ctx.Route() == self {
+		return "unmatched"
+	}
+	return ctx.Route().Path
+}
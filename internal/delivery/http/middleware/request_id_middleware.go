@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+)
+
+// NewRequestID accepts the caller's X-Request-ID or generates one, echoes it
+// in the response and stores a logger entry carrying it and the method in
+// the user context. Authentication later adds the user id and NewRouteLog
+// the route template. The raw path is left out, it is unbounded before
+// routing.
+func NewRequestID(log *logrus.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		requestId := ctx.Get(fiber.HeaderXRequestID)
+		if !validRequestID(requestId) {
+			requestId = uuid.NewString()
+		} else {
+			requestId = utils.CopyString(requestId)
+		}
+		ctx.Set(fiber.HeaderXRequestID, requestId)
+		ctx.Locals("requestId", requestId)
+
+		entry := log.WithFields(logrus.Fields{
+			"request_id": requestId,
+			"method":     utils.CopyString(ctx.Method()),
+		})
+		ctx.SetUserContext(pkg.ContextWithLogger(ctx.UserContext(), entry))
+		return ctx.Next()
+	}
+}
+
+// NewRouteLog adds the template of the matched route to the request logger.
+// It has to be a handler of the route itself: middleware registered with Use
+// runs before routing and only sees its own route.
+func NewRouteLog(log *logrus.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		entry := pkg.Logger(ctx.UserContext(), log).WithField("route", ctx.Route().Path)
+		ctx.SetUserContext(pkg.ContextWithLogger(ctx.UserContext(), entry))
+		return ctx.Next()
+	}
+}
+
+// GetRequestID returns the id NewRequestID assigned to the request.
+func GetRequestID(ctx *fiber.Ctx) string {
+	requestId, _ := ctx.Locals("requestId").(string)
+	return requestId
+}
+
+// validRequestID keeps caller supplied ids short and free of characters
+// that could forge log lines or headers.
+func validRequestID(requestId string) bool {
+	if requestId == "" || len(requestId) > 128 {
+		return false
+	}
+	for _, r := range requestId {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' || r == ':') {
+			return false
+		}
+	}
+	return true
+}
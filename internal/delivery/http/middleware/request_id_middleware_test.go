@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/manikandareas/go-clean-architecture/internal/delivery/http"
+	"github.com/manikandareas/go-clean-architecture/internal/delivery/http/middleware"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/repository/memory"
+	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRouteLog(t *testing.T) {
+	var output bytes.Buffer
+	newLog := func() *logrus.Logger {
+		log := logrus.New()
+		log.SetOutput(&output)
+		log.SetFormatter(&logrus.JSONFormatter{})
+		return log
+	}
+	httpLog, useCaseLog := newLog(), newLog()
+
+	store := memory.NewStore()
+	appConfig := &model.AppConfig{Pagination: model.PaginationConfig{CursorSecret: "cursor-secret-for-the-unit-tests"}}
+	bookUseCase := usecase.NewBookUseCase(memory.NewTxManager(store), useCaseLog, validator.New(), memory.NewBookRepository(store), memory.NewAuthorRepository(store), pkg.NewCursorService(appConfig))
+	bookController := http.NewBookController(bookUseCase, httpLog)
+
+	app := fiber.New()
+	app.Use(middleware.NewRequestID(httpLog))
+	app.Get("/api/books/:bookId", middleware.NewRouteLog(httpLog), bookController.Get)
+
+	request := httptest.NewRequest(fiber.MethodGet, "/api/books/missing", nil)
+	request.Header.Set(fiber.HeaderXRequestID, "request-1")
+	response, err := app.Test(request)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if response.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("status = %d, want %d", response.StatusCode, fiber.StatusNotFound)
+	}
+
+	var found bool
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("log line %s is not JSON: %v", line, err)
+		}
+		if fields["msg"] != "failed to find book" {
+			continue
+		}
+		found = true
+		if fields["route"] != "/api/books/:bookId" || fields["request_id"] != "request-1" {
+			t.Fatalf("use case log line %s lacks the route or request id", line)
+		}
+	}
+	if !found {
+		t.Fatalf("no use case log line in %s", output.String())
+	}
+}
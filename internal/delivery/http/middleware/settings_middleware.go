@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"sync"
+)
+
+// NewRateLimit limits requests per client IP to web.rateLimit. The limiter is
+// rebuilt when a configuration reload changes the limits, which starts every
+// client with a fresh window.
+func NewRateLimit(settings *pkg.Settings) fiber.Handler {
+	var mutex sync.Mutex
+	var current model.RateLimitConfig
+	var handler fiber.Handler
+
+	return func(ctx *fiber.Ctx) error {
+		config := settings.Get().Web.RateLimit
+		if config.Max == 0 {
+			return ctx.Next()
+		}
+
+		mutex.Lock()
+		if handler == nil || config != current {
+			current = config
+			handler = limiter.New(limiter.Config{
+				Max:        config.Max,
+				Expiration: config.Expiration,
+				LimitReached: func(ctx *fiber.Ctx) error {
+					return fiber.ErrTooManyRequests
+				},
+			})
+		}
+		limit := handler
+		mutex.Unlock()
+
+		return limit(ctx)
+	}
+}
+
+// NewFeature returns a guard factory for feature flags. Routes behind a
+// disabled feature answer 404 as if they did not exist.
+func NewFeature(settings *pkg.Settings) func(feature string) fiber.Handler {
+	return func(feature string) fiber.Handler {
+		return func(ctx *fiber.Ctx) error {
+			if !settings.Get().FeatureEnabled(feature) {
+				return fiber.ErrNotFound
+			}
+			return ctx.Next()
+		}
+	}
+}
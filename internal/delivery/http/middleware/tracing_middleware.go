@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// NewTracing continues the trace of an incoming W3C traceparent header, or
+// starts a new one, and stores the server span in the user context that the
+// controllers hand to the use cases. The span context is echoed back in the
+// traceparent response header.
+func NewTracing() fiber.Handler {
+	tracer := otel.Tracer("github.com/manikandareas/go-clean-architecture/internal/delivery/http")
+
+	return func(ctx *fiber.Ctx) error {
+		propagator := otel.GetTextMapPropagator()
+		self := ctx.Route()
+		method := utils.CopyString(ctx.Method())
+
+		parent := propagator.Extract(ctx.UserContext(), propagation.HeaderCarrier(http.Header(ctx.GetReqHeaders())))
+		spanContext, span := tracer.Start(parent, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", method),
+				attribute.String("http.target", utils.CopyString(ctx.OriginalURL())),
+			),
+		)
+		defer span.End()
+
+		ctx.SetUserContext(spanContext)
+		propagator.Inject(spanContext, responseHeaderCarrier{ctx: ctx})
+
+		status := next(ctx)
+		if ctx.Route() != self {
+			span.SetName(method + " " + ctx.Route().Path)
+			span.SetAttributes(attribute.String("http.route", ctx.Route().Path))
+		}
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, utils.StatusMessage(status))
+		}
+		return nil
+	}
+}
+
+type responseHeaderCarrier struct {
+	ctx *fiber.Ctx
+}
+
+func (c responseHeaderCarrier) Get(key string) string {
+	return string(c.ctx.Response().Header.Peek(key))
+}
+
+func (c responseHeaderCarrier) Set(key string, value string) {
+	c.ctx.Set(key, value)
+}
+
+func (c responseHeaderCarrier) Keys() []string {
+	keys := make([]string, 0)
+	c.ctx.Response().Header.VisitAll(func(key, value []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+)
+
+type RoleController struct {
+	UseCase *usecase.RoleUseCase
+	Log     *logrus.Logger
+}
+
+func NewRoleController(useCase *usecase.RoleUseCase, log *logrus.Logger) *RoleController {
+	return &RoleController{
+		UseCase: useCase,
+		Log:     log,
+	}
+}
+
+func (c *RoleController) FindAll(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.List(ctx.UserContext())
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to get roles")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": response})
+}
+
+func (c *RoleController) Assign(ctx *fiber.Ctx) error {
+	request := new(model.AssignRoleRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to parse request body")
+		return fiber.ErrBadRequest
+	}
+	request.UserId = ctx.Params("userId")
+
+	response, err := c.UseCase.Assign(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), c.Log).WithError(err).Error("failed to assign roles")
+		return err
+	}
+	return ctx.JSON(fiber.Map{"data": response})
+}
@@ -3,31 +3,90 @@ package route
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/manikandareas/go-clean-architecture/internal/delivery/http"
+	"github.com/manikandareas/go-clean-architecture/internal/delivery/http/middleware"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
 )
 
 type RouteConfig struct {
 	App                    *fiber.App
+	MetricsApp             *fiber.App
+	RequestIDMiddleware    fiber.Handler
+	RouteLogMiddleware     fiber.Handler
+	AccessLogMiddleware    fiber.Handler
 	BookController         *http.BookController
+	AuthorController       *http.AuthorController
 	UserController         *http.UserController
+	RoleController         *http.RoleController
+	HealthController       *http.HealthController
 	AuthMiddleware         fiber.Handler
 	RefreshTokenMiddleware fiber.Handler
+	RateLimitMiddleware    fiber.Handler
+	FeatureMiddleware      func(feature string) fiber.Handler
+	MetricsMiddleware      fiber.Handler
+	TracingMiddleware      fiber.Handler
+	MetricsHandler         fiber.Handler
+	MetricsAllowMiddleware fiber.Handler
 }
 
 func (c *RouteConfig) Setup() {
+	c.App.Use(c.RequestIDMiddleware)
+	c.App.Use(c.AccessLogMiddleware)
+	c.App.Use(c.MetricsMiddleware)
+	c.App.Use(c.TracingMiddleware)
 	c.SetupGuestRoute()
 	c.SetupAuthRoute()
+	c.SetupMetricsRoute()
 }
 
 func (c *RouteConfig) SetupGuestRoute() {
+	c.App.Get("/healthz", c.HealthController.Live)
+	c.App.Get("/readyz", c.HealthController.Ready)
+
 	api := c.App.Group("api")
+	// the first handler of every route, so later log lines carry its template
+	logRoute := c.RouteLogMiddleware
+
+	api.Post("/users", logRoute, c.RateLimitMiddleware, c.FeatureMiddleware(model.FeatureRegistration), c.UserController.Register)
+	api.Post("/users/_login", logRoute, c.RateLimitMiddleware, c.UserController.Login)
+	api.Post("/users/_refresh", logRoute, c.RateLimitMiddleware, c.RefreshTokenMiddleware, c.UserController.RefreshToken)
+}
 
-	api.Post("/users", c.UserController.Register)
-	api.Post("/users/_login", c.UserController.Login)
-	api.Post("/users/_refresh", c.RefreshTokenMiddleware, c.UserController.RefreshToken)
+// SetupMetricsRoute serves /metrics on MetricsApp, the listener of
+// metrics.port, or on the public App when there is none.
+func (c *RouteConfig) SetupMetricsRoute() {
+	app := c.App
+	if c.MetricsApp != nil {
+		app = c.MetricsApp
+	}
+	app.Get("/metrics", c.MetricsAllowMiddleware, c.MetricsHandler)
 }
 
 func (c *RouteConfig) SetupAuthRoute() {
 	api := c.App.Group("api", c.AuthMiddleware)
-	api.Get("/books", c.BookController.FindAll)
-	api.Post("/books", c.BookController.Create)
+	logRoute := c.RouteLogMiddleware
+	api.Get("/users/_current", logRoute, c.UserController.Current)
+	api.Patch("/users/_current", logRoute, c.UserController.Update)
+	api.Delete("/users/_logout", logRoute, c.UserController.Logout)
+
+	readBooks := middleware.RequirePermission(model.PermissionBooksRead)
+	writeBooks := middleware.RequirePermission(model.PermissionBooksWrite)
+	api.Get("/books", logRoute, readBooks, c.BookController.FindAll)
+	api.Post("/books", logRoute, writeBooks, c.BookController.Create)
+	api.Get("/books/:bookId", logRoute, readBooks, c.BookController.Get)
+	api.Put("/books/:bookId", logRoute, writeBooks, c.BookController.Update)
+	api.Patch("/books/:bookId", logRoute, writeBooks, c.BookController.Update)
+	api.Delete("/books/:bookId", logRoute, writeBooks, c.BookController.Delete)
+
+	readAuthors := middleware.RequirePermission(model.PermissionAuthorsRead)
+	writeAuthors := middleware.RequirePermission(model.PermissionAuthorsWrite)
+	api.Get("/authors", logRoute, readAuthors, c.AuthorController.FindAll)
+	api.Post("/authors", logRoute, writeAuthors, c.AuthorController.Create)
+	api.Get("/authors/:authorId", logRoute, readAuthors, c.AuthorController.Get)
+	api.Put("/authors/:authorId", logRoute, writeAuthors, c.AuthorController.Update)
+	api.Patch("/authors/:authorId", logRoute, writeAuthors, c.AuthorController.Update)
+	api.Delete("/authors/:authorId", logRoute, writeAuthors, c.AuthorController.Delete)
+
+	assignRoles := middleware.RequirePermission(model.PermissionRolesAssign)
+	api.Get("/roles", logRoute, assignRoles, c.RoleController.FindAll)
+	api.Put("/users/:userId/roles", logRoute, assignRoles, c.RoleController.Assign)
 }
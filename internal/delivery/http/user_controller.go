@@ -5,6 +5,7 @@ import (
 	"github.com/manikandareas/go-clean-architecture/internal/delivery/http/middleware"
 	"github.com/manikandareas/go-clean-architecture/internal/model"
 	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"github.com/manikandareas/go-clean-architecture/pkg"
 	"github.com/sirupsen/logrus"
 )
 
@@ -21,13 +22,13 @@ func (u *UserController) Register(ctx *fiber.Ctx) error {
 	request := new(model.RegisterUserRequest)
 
 	if err := ctx.BodyParser(request); err != nil {
-		u.Log.WithError(err).Error("failed to parse request body")
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to parse request body")
 		return fiber.ErrBadRequest
 	}
 
-	response, err := u.UseCase.Register(ctx.Context(), request)
+	response, err := u.UseCase.Register(ctx.UserContext(), request)
 	if err != nil {
-		u.Log.WithError(err).Error("failed to register user")
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to register user")
 		return err
 	}
 
@@ -38,13 +39,13 @@ func (u *UserController) Login(ctx *fiber.Ctx) error {
 	request := new(model.LoginUserRequest)
 
 	if err := ctx.BodyParser(request); err != nil {
-		u.Log.WithError(err).Error("failed to parse request body")
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to parse request body")
 		return fiber.ErrBadRequest
 	}
 
-	response, err := u.UseCase.Login(ctx.Context(), request)
+	response, err := u.UseCase.Login(ctx.UserContext(), request)
 	if err != nil {
-		u.Log.WithError(err).Error("failed to login user")
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to login user")
 		return err
 	}
 
@@ -52,13 +53,75 @@ func (u *UserController) Login(ctx *fiber.Ctx) error {
 }
 
 func (u *UserController) RefreshToken(ctx *fiber.Ctx) error {
-	request := middleware.GetUser(ctx)
+	auth := middleware.GetUser(ctx)
 
-	response, err := u.UseCase.RefreshToken(ctx.Context(), request)
+	request := &model.RefreshTokenRequest{
+		ID:    auth.ID,
+		Token: middleware.GetToken(ctx),
+	}
+
+	response, err := u.UseCase.RefreshToken(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to refresh token")
+		return err
+	}
+
+	return ctx.JSON(fiber.Map{"data": response})
+}
+
+func (u *UserController) Current(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.GetUserRequest{
+		ID: auth.ID,
+	}
+
+	response, err := u.UseCase.Current(ctx.UserContext(), request)
+	if err != nil {
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to get current user")
+		return err
+	}
+
+	return ctx.JSON(fiber.Map{"data": response})
+}
+
+func (u *UserController) Update(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.UpdateUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to parse request body")
+		return fiber.ErrBadRequest
+	}
+	request.ID = auth.ID
+
+	response, err := u.UseCase.Update(ctx.UserContext(), request)
 	if err != nil {
-		u.Log.WithError(err).Error("failed to refresh token")
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to update user")
 		return err
 	}
 
 	return ctx.JSON(fiber.Map{"data": response})
 }
+
+func (u *UserController) Logout(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := new(model.LogoutUserRequest)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(request); err != nil {
+			pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to parse request body")
+			return fiber.ErrBadRequest
+		}
+	}
+	request.ID = auth.ID
+	request.TokenID = auth.TokenID
+	request.ExpiresAt = auth.ExpiresAt
+
+	if err := u.UseCase.Logout(ctx.UserContext(), request); err != nil {
+		pkg.Logger(ctx.UserContext(), u.Log).WithError(err).Error("failed to logout user")
+		return err
+	}
+
+	return ctx.JSON(fiber.Map{"data": true})
+}
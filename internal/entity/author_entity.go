@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+type Author struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	Name      string    `gorm:"column:name"`
+	Bio       string    `gorm:"column:bio"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	Books     []Book    `gorm:"foreignKey:AuthorId;references:ID"`
+}
+
+func (a *Author) TableName() string {
+	return "authors"
+}
@@ -1,9 +1,15 @@
 package entity
 
+import "time"
+
 type Book struct {
-	ID       string `gorm:"column:id;primaryKey"`
-	Title    string `gorm:"column:title"`
-	AuthorId string `gorm:"column:author_id"`
+	ID        string    `gorm:"column:id;primaryKey"`
+	Title     string    `gorm:"column:title"`
+	AuthorId  string    `gorm:"column:author_id;index"`
+	OwnerId   string    `gorm:"column:owner_id;index"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	Author    *Author   `gorm:"foreignKey:AuthorId;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 }
 
 func (b *Book) TableName() string {
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type Permission struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	Name      string    `gorm:"column:name;uniqueIndex"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (p *Permission) TableName() string {
+	return "permissions"
+}
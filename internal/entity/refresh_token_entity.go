@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// RefreshToken is the persisted, hashed form of an issued refresh token.
+// Tokens rotated from the same login share a FamilyId so that a replayed
+// token can revoke every descendant at once.
+type RefreshToken struct {
+	ID        string     `gorm:"column:id;primaryKey"`
+	FamilyId  string     `gorm:"column:family_id;index"`
+	UserId    string     `gorm:"column:user_id;index"`
+	TokenHash string     `gorm:"column:token_hash;uniqueIndex"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;index"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (r *RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// RevokedToken is a denylist entry keyed by the jti claim of a JWT. Entries
+// are only needed until the token would have expired on its own.
+type RevokedToken struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	UserId    string    `gorm:"column:user_id;index"`
+	ExpiresAt time.Time `gorm:"column:expires_at;index"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (r *RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
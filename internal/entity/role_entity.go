@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type Role struct {
+	ID          string       `gorm:"column:id;primaryKey"`
+	Name        string       `gorm:"column:name;uniqueIndex"`
+	Permissions []Permission `gorm:"many2many:role_permissions;"`
+	CreatedAt   time.Time    `gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt   time.Time    `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+}
+
+func (r *Role) TableName() string {
+	return "roles"
+}
@@ -10,6 +10,7 @@ type User struct {
 	Token     string    `gorm:"column:token"`
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime:milli;autoUpdateTime:milli"`
+	Roles     []Role    `gorm:"many2many:user_roles;"`
 }
 
 func (u *User) TableName() string {
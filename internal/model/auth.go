@@ -1,17 +1,51 @@
 package model
 
-import "github.com/golang-jwt/jwt/v4"
+import (
+	"github.com/golang-jwt/jwt/v4"
+	"slices"
+	"time"
+)
+
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleUser   = "user"
+)
+
+const (
+	PermissionBooksRead    = "books:read"
+	PermissionBooksWrite   = "books:write"
+	PermissionAuthorsRead  = "authors:read"
+	PermissionAuthorsWrite = "authors:write"
+	PermissionRolesAssign  = "roles:assign"
+)
 
 type Auth struct {
 	// Login user id
 	ID    string
 	Email string
 	Name  string
+	// jti and expiry of the token the user authenticated with
+	TokenID   string
+	ExpiresAt time.Time
+	// granted at the time the token was issued
+	Roles       []string
+	Permissions []string
+}
+
+func (a *Auth) HasRole(role string) bool {
+	return slices.Contains(a.Roles, role)
+}
+
+func (a *Auth) HasPermission(permission string) bool {
+	return slices.Contains(a.Permissions, permission)
 }
 
 type JwtClaims struct {
 	jwt.RegisteredClaims
-	User map[string]string
+	User        map[string]string
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
 }
 
 type BackendTokens struct {
@@ -0,0 +1,34 @@
+package model
+
+type AuthorResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Bio       string `json:"bio,omitempty"`
+	CreatedAt int64  `json:"created_at,omitempty"`
+	UpdatedAt int64  `json:"updated_at,omitempty"`
+}
+
+type AuthorRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Bio  string `json:"bio" validate:"max=1000"`
+}
+
+type GetAuthorRequest struct {
+	ID string `json:"-" validate:"required,max=100"`
+}
+
+type UpdateAuthorRequest struct {
+	ID   string `json:"-" validate:"required,max=100"`
+	Name string `json:"name,omitempty" validate:"max=100"`
+	Bio  string `json:"bio,omitempty" validate:"max=1000"`
+}
+
+type DeleteAuthorRequest struct {
+	ID string `json:"-" validate:"required,max=100"`
+}
+
+type SearchAuthorRequest struct {
+	Name string `json:"name" validate:"max=100"`
+	Page int    `json:"page" validate:"min=1"`
+	Size int    `json:"size" validate:"min=1,max=100"`
+}
@@ -1,12 +1,46 @@
 package model
 
 type BookResponse struct {
-	ID       string `json:"id"`
-	Title    string `json:"title"`
-	AuthorId string `json:"author_id"`
+	ID        string          `json:"id"`
+	Title     string          `json:"title"`
+	AuthorId  string          `json:"author_id"`
+	OwnerId   string          `json:"owner_id,omitempty"`
+	CreatedAt int64           `json:"created_at,omitempty"`
+	UpdatedAt int64           `json:"updated_at,omitempty"`
+	Author    *AuthorResponse `json:"author,omitempty"`
 }
 
 type BookRequest struct {
+	UserId   string `json:"-" validate:"required,max=100"`
 	Title    string `json:"title"  validate:"required"`
 	AuthorId string `json:"author_id"  validate:"required"`
 }
+
+type GetBookRequest struct {
+	ID            string `json:"-" validate:"required,max=100"`
+	IncludeAuthor bool   `json:"-"`
+}
+
+type UpdateBookRequest struct {
+	ID       string `json:"-" validate:"required,max=100"`
+	UserId   string `json:"-" validate:"required,max=100"`
+	IsAdmin  bool   `json:"-"`
+	Title    string `json:"title,omitempty"`
+	AuthorId string `json:"author_id,omitempty"`
+}
+
+type DeleteBookRequest struct {
+	ID      string `json:"-" validate:"required,max=100"`
+	UserId  string `json:"-" validate:"required,max=100"`
+	IsAdmin bool   `json:"-"`
+}
+
+type SearchBookRequest struct {
+	Title         string `json:"title" validate:"max=100"`
+	AuthorId      string `json:"author_id" validate:"max=100"`
+	Sort          string `json:"sort" validate:"omitempty,oneof=title -title author_id -author_id created_at -created_at"`
+	After         string `json:"after" validate:"max=1024"`
+	Page          int    `json:"page" validate:"min=1"`
+	Size          int    `json:"size" validate:"min=1,max=100"`
+	IncludeAuthor bool   `json:"-"`
+}
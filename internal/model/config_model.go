@@ -0,0 +1,139 @@
+package model
+
+import "time"
+
+const FeatureRegistration = "registration"
+
+// packages that log.packages may run at their own level
+const (
+	LogPackageGorm       = "gorm"
+	LogPackageRepository = "repository"
+	LogPackageUseCase    = "usecase"
+	LogPackageHttp       = "http"
+)
+
+// AppConfig mirrors config.json. Field names follow the json keys, so
+// validation errors point at the exact key to fix.
+type AppConfig struct {
+	App        AppSectionConfig `json:"app"`
+	Web        WebConfig        `json:"web"`
+	Log        LogConfig        `json:"log"`
+	Database   DatabaseConfig   `json:"database"`
+	Jwt        JwtConfig        `json:"jwt"`
+	Rbac       RbacConfig       `json:"rbac"`
+	Pagination PaginationConfig `json:"pagination"`
+	Tracing    TracingConfig    `json:"tracing"`
+	Metrics    MetricsConfig    `json:"metrics"`
+	// feature flags by name, see FeatureEnabled
+	Features map[string]bool `json:"features"`
+}
+
+// FeatureEnabled reports whether the named feature is switched on. Features
+// missing from the configuration keep their default, which is on.
+func (c *AppConfig) FeatureEnabled(name string) bool {
+	enabled, ok := c.Features[name]
+	return !ok || enabled
+}
+
+type AppSectionConfig struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type WebConfig struct {
+	Prefork   bool            `json:"prefork"`
+	Port      int             `json:"port" validate:"required,min=1,max=65535"`
+	RateLimit RateLimitConfig `json:"rateLimit"`
+	// how long in-flight requests may take to finish once shutdown starts
+	ShutdownTimeout time.Duration `json:"shutdownTimeout" validate:"min=1s"`
+}
+
+// RateLimitConfig caps requests per client IP on the guest routes. A Max of
+// zero turns the limiter off.
+type RateLimitConfig struct {
+	Max        int           `json:"max" validate:"min=0"`
+	Expiration time.Duration `json:"expiration" validate:"min=1s"`
+}
+
+type LogConfig struct {
+	// logrus level name, panic through trace, or its number from 0 to 6
+	Level  string `json:"level" validate:"loglevel"`
+	Format string `json:"format" validate:"omitempty,oneof=json text"`
+	// defaults to stdout when empty
+	Outputs []LogOutputConfig `json:"outputs" validate:"dive"`
+	// level overrides for the LogPackage* loggers, e.g. {"gorm": "warn"}
+	Packages map[string]string `json:"packages" validate:"dive,keys,oneof=gorm repository usecase http,endkeys,loglevel"`
+	// log SQL parameter values, redacted, instead of bare placeholders
+	SqlParams bool `json:"sqlParams"`
+}
+
+type LogOutputConfig struct {
+	Type string `json:"type" validate:"oneof=stdout stderr file syslog"`
+	// file: rotated once it reaches MaxSize megabytes or every RotateEvery
+	Path        string        `json:"path" validate:"required_if=Type file"`
+	MaxSize     int           `json:"maxSize" validate:"min=0"`
+	MaxAge      int           `json:"maxAge" validate:"min=0"`
+	MaxBackups  int           `json:"maxBackups" validate:"min=0"`
+	RotateEvery time.Duration `json:"rotateEvery" validate:"omitempty,min=1m"`
+	Compress    bool          `json:"compress"`
+	// syslog: the local daemon unless Network and Address are set
+	Network string `json:"network" validate:"omitempty,oneof=udp tcp unix unixgram"`
+	Address string `json:"address" validate:"required_with=Network"`
+	Tag     string `json:"tag"`
+}
+
+type DatabaseConfig struct {
+	Driver      string             `json:"driver" validate:"omitempty,oneof=mysql postgres sqlite"`
+	Username    string             `json:"username" validate:"required_unless=Driver sqlite"`
+	Password    string             `json:"password"`
+	Host        string             `json:"host" validate:"required_unless=Driver sqlite"`
+	Port        int                `json:"port" validate:"required_unless=Driver sqlite,omitempty,min=1,max=65535"`
+	Name        string             `json:"name" validate:"required"`
+	SslMode     string             `json:"sslmode" validate:"omitempty,oneof=disable allow prefer require verify-ca verify-full"`
+	Timezone    string             `json:"timezone"`
+	Path        string             `json:"path"`
+	AutoMigrate bool               `json:"autoMigrate"`
+	Pool        DatabasePoolConfig `json:"pool"`
+}
+
+type DatabasePoolConfig struct {
+	Idle int `json:"idle" validate:"min=0"`
+	Max  int `json:"max" validate:"min=1"`
+	// seconds
+	Lifetime int `json:"lifetime" validate:"min=0"`
+}
+
+type JwtConfig struct {
+	AccessToken     string        `json:"accessToken" validate:"required,min=32,nefield=RefreshToken"`
+	RefreshToken    string        `json:"refreshToken" validate:"required,min=32"`
+	AccessTokenTtl  time.Duration `json:"accessTokenTtl" validate:"min=1m"`
+	RefreshTokenTtl time.Duration `json:"refreshTokenTtl" validate:"min=1m,gtfield=AccessTokenTtl"`
+	// how often records of expired tokens are deleted
+	CleanupInterval time.Duration `json:"cleanupInterval" validate:"min=1m"`
+}
+
+type RbacConfig struct {
+	// emails promoted to admin at startup, removing one does not demote it
+	Admins []string `json:"admins" validate:"dive,email"`
+}
+
+// TracingConfig selects where OpenTelemetry spans go: nowhere, stdout, a
+// file of JSON lines, or an OTLP/HTTP collector.
+type TracingConfig struct {
+	Exporter    string  `json:"exporter" validate:"omitempty,oneof=none stdout file otlp"`
+	File        string  `json:"file" validate:"required_if=Exporter file"`
+	Endpoint    string  `json:"endpoint" validate:"required_if=Exporter otlp"`
+	Insecure    bool    `json:"insecure"`
+	SampleRatio float64 `json:"sampleRatio" validate:"min=0,max=1"`
+}
+
+// MetricsConfig guards the Prometheus endpoint. A Port serves /metrics on a
+// listener of its own instead of the public one, and AllowedNetworks limits
+// the clients that may scrape it; an empty list lets every client through.
+type MetricsConfig struct {
+	Port            int      `json:"port" validate:"omitempty,min=1,max=65535"`
+	AllowedNetworks []string `json:"allowedNetworks" validate:"dive,cidr"`
+}
+
+type PaginationConfig struct {
+	CursorSecret string `json:"cursorSecret" validate:"required,min=32"`
+}
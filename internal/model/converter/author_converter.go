@@ -0,0 +1,24 @@
+package converter
+
+import (
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+)
+
+func AuthorsToResponse(authors *[]entity.Author) []model.AuthorResponse {
+	authorsResponse := make([]model.AuthorResponse, 0, len(*authors))
+	for _, author := range *authors {
+		authorsResponse = append(authorsResponse, *AuthorToResponse(&author))
+	}
+	return authorsResponse
+}
+
+func AuthorToResponse(author *entity.Author) *model.AuthorResponse {
+	return &model.AuthorResponse{
+		ID:        author.ID,
+		Name:      author.Name,
+		Bio:       author.Bio,
+		CreatedAt: unixTime(author.CreatedAt),
+		UpdatedAt: unixTime(author.UpdatedAt),
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 func BooksToResponse(books *[]entity.Book) []model.BookResponse {
-	var booksResponse []model.BookResponse
+	booksResponse := make([]model.BookResponse, 0, len(*books))
 	for _, book := range *books {
 		booksResponse = append(booksResponse, *BookToResponse(&book))
 	}
@@ -14,9 +14,16 @@ func BooksToResponse(books *[]entity.Book) []model.BookResponse {
 }
 
 func BookToResponse(book *entity.Book) *model.BookResponse {
-	return &model.BookResponse{
-		ID:       book.ID,
-		Title:    book.Title,
-		AuthorId: book.AuthorId,
+	response := &model.BookResponse{
+		ID:        book.ID,
+		Title:     book.Title,
+		AuthorId:  book.AuthorId,
+		OwnerId:   book.OwnerId,
+		CreatedAt: unixTime(book.CreatedAt),
+		UpdatedAt: unixTime(book.UpdatedAt),
 	}
+	if book.Author != nil {
+		response.Author = AuthorToResponse(book.Author)
+	}
+	return response
 }
@@ -0,0 +1,26 @@
+package converter
+
+import (
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+)
+
+func RolesToResponse(roles *[]entity.Role) []model.RoleResponse {
+	rolesResponse := make([]model.RoleResponse, 0, len(*roles))
+	for _, role := range *roles {
+		rolesResponse = append(rolesResponse, *RoleToResponse(&role))
+	}
+	return rolesResponse
+}
+
+func RoleToResponse(role *entity.Role) *model.RoleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		permissions = append(permissions, permission.Name)
+	}
+	return &model.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Permissions: permissions,
+	}
+}
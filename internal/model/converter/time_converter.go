@@ -0,0 +1,12 @@
+package converter
+
+import "time"
+
+// unixTime converts t to Unix seconds, leaving an unset time at 0 so that
+// omitempty drops it instead of reporting a date in year 1.
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -1,29 +1,28 @@
 package converter
 
 import (
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/manikandareas/go-clean-architecture/internal/entity"
 	"github.com/manikandareas/go-clean-architecture/internal/model"
 )
 
 func UserToResponse(user *entity.User) *model.UserResponse {
-	return &model.UserResponse{
+	response := &model.UserResponse{
 		ID:        user.ID,
 		Name:      user.Name,
 		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Unix(),
-		UpdatedAt: user.UpdatedAt.Unix(),
+		CreatedAt: unixTime(user.CreatedAt),
+		UpdatedAt: unixTime(user.UpdatedAt),
 	}
+	for _, role := range user.Roles {
+		response.Roles = append(response.Roles, role.Name)
+	}
+	return response
 }
 
-func UserToLoginResponse(user *entity.User, expiresIn *jwt.NumericDate, token ...string) *model.LoginUserResponse {
+func UserToLoginResponse(user *entity.User, backendTokens *model.BackendTokens) *model.LoginUserResponse {
 	return &model.LoginUserResponse{
-		User: UserToResponse(user),
-		BackendTokens: model.BackendTokens{
-			AccessToken:  token[0],
-			RefreshToken: token[1],
-			ExpiresIn:    expiresIn.Unix(),
-		},
+		User:          UserToResponse(user),
+		BackendTokens: *backendTokens,
 	}
 }
 
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const (
+	ErrorCodeValidation = "validation_failed"
+)
+
+const validationMessage = "Request validation failed"
+
+// Error is a domain error carrying the HTTP status it maps to, a machine
+// readable code and, for validation failures, the offending fields.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Fields  []FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorResponse is the body rendered into WebResponse.Errors.
+type ErrorResponse struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// ProblemDetails is the RFC 7807 rendering of an Error.
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Fields   []FieldError `json:"fields,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+	// source keeps the validator error so it can use its own translations
+	source validator.FieldError
+}
+
+func NewError(status int, code string, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// NewFieldError reports a single invalid field that the validator cannot
+// check on its own, such as a reference to a missing record.
+func NewFieldError(field string, tag string, param string, message string) *Error {
+	return &Error{
+		Status:  fiber.StatusBadRequest,
+		Code:    ErrorCodeValidation,
+		Message: validationMessage,
+		Fields:  []FieldError{{Field: field, Tag: tag, Param: param, Message: message}},
+	}
+}
+
+// NewValidationError translates the result of validator.Struct into an
+// Error listing every failed field.
+func NewValidationError(err error) *Error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return NewError(fiber.StatusBadRequest, ErrorCodeValidation, err.Error())
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		fields = append(fields, FieldError{
+			Field:   fieldError.Field(),
+			Tag:     fieldError.Tag(),
+			Param:   fieldError.Param(),
+			Message: fieldErrorMessage(fieldError),
+			source:  fieldError,
+		})
+	}
+	return &Error{
+		Status:  fiber.StatusBadRequest,
+		Code:    ErrorCodeValidation,
+		Message: validationMessage,
+		Fields:  fields,
+	}
+}
+
+// Translate returns a copy of the error with every field message localized.
+// The message is looked up by code only while it is the generic one for the
+// status, so specific messages such as "Cannot GET /x" are kept. Messages
+// without a translation are kept as they are.
+func (e *Error) Translate(trans ut.Translator) *Error {
+	translated := *e
+	if e.Message == utils.StatusMessage(e.Status) || e.Message == validationMessage {
+		if message, err := trans.T(e.Code); err == nil {
+			translated.Message = message
+		}
+	}
+
+	translated.Fields = make([]FieldError, len(e.Fields))
+	for i, field := range e.Fields {
+		translated.Fields[i] = field
+		if field.source != nil {
+			// the validator falls back to its raw error for unknown tags
+			if message := field.source.Translate(trans); message != field.source.Error() {
+				translated.Fields[i].Message = message
+			}
+		} else if message, err := trans.T("field_"+field.Tag, field.Field, field.Param); err == nil {
+			translated.Fields[i].Message = message
+		}
+	}
+	return &translated
+}
+
+func fieldErrorMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required", "required_with":
+		return fmt.Sprintf("%s is required", fieldError.Field())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", fieldError.Field(), fieldError.Param())
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", fieldError.Field(), fieldError.Param())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", fieldError.Field())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", fieldError.Field(), fieldError.Param())
+	default:
+		return fmt.Sprintf("%s is invalid", fieldError.Field())
+	}
+}
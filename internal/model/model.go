@@ -1,9 +1,11 @@
 package model
 
+import "time"
+
 type WebResponse[T any] struct {
-	Data   T             `json:"data"`
-	Paging *PageMetadata `json:"paging,omitempty"`
-	Errors string        `json:"errors,omitempty"`
+	Data   T              `json:"data"`
+	Paging *PageMetadata  `json:"paging,omitempty"`
+	Errors *ErrorResponse `json:"errors,omitempty"`
 }
 
 type PageResponse[T any] struct {
@@ -11,9 +13,32 @@ type PageResponse[T any] struct {
 	PageMetadata PageMetadata `json:"paging,omitempty"`
 }
 
+// PageMetadata describes a page of a listing. Offset pagination sets
+// OffsetPage and cursor pagination sets CursorPage, so each mode only
+// renders its own fields.
 type PageMetadata struct {
+	*OffsetPage
+	Size int `json:"size"`
+	*CursorPage
+}
+
+type OffsetPage struct {
 	Page      int   `json:"page"`
-	Size      int   `json:"size"`
 	TotalItem int64 `json:"total_item"`
 	TotalPage int64 `json:"total_page"`
 }
+
+type CursorPage struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Cursor is a keyset position in a listing ordered by creation time and id.
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+	// Desc records the listing order so the cursor stays self-contained.
+	Desc bool `json:"d,omitempty"`
+	// Backward pages towards the start of the listing (a prev cursor).
+	Backward bool `json:"b,omitempty"`
+}
@@ -0,0 +1,12 @@
+package model
+
+type RoleResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type AssignRoleRequest struct {
+	UserId string   `json:"-" validate:"required,max=100"`
+	Roles  []string `json:"roles" validate:"required,min=1,dive,required,max=100"`
+}
@@ -1,12 +1,15 @@
 package model
 
+import "time"
+
 type UserResponse struct {
-	ID        string `json:"id,omitempty"`
-	Name      string `json:"name,omitempty"`
-	Email     string `json:"email,omitempty"`
-	Token     string `json:"token,omitempty"`
-	CreatedAt int64  `json:"created_at,omitempty"`
-	UpdatedAt int64  `json:"updated_at,omitempty"`
+	ID        string   `json:"id,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Token     string   `json:"token,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	CreatedAt int64    `json:"created_at,omitempty"`
+	UpdatedAt int64    `json:"updated_at,omitempty"`
 }
 
 type VerifyUserRequest struct {
@@ -20,10 +23,11 @@ type RegisterUserRequest struct {
 }
 
 type UpdateUserRequest struct {
-	ID       string `json:"-" validate:"required,max=100"`
-	Email    string `json:"email,omitempty"`
-	Password string `json:"password,omitempty" validate:"max=100"`
-	Name     string `json:"name,omitempty" validate:"max=100"`
+	ID              string `json:"-" validate:"required,max=100"`
+	Email           string `json:"email,omitempty" validate:"omitempty,email,max=100"`
+	Password        string `json:"password,omitempty" validate:"max=100"`
+	CurrentPassword string `json:"current_password,omitempty" validate:"required_with=Password,max=100"`
+	Name            string `json:"name,omitempty" validate:"max=100"`
 }
 
 type LoginUserRequest struct {
@@ -31,13 +35,21 @@ type LoginUserRequest struct {
 	Password string `json:"password" validate:"required,max=100"`
 }
 
+type RefreshTokenRequest struct {
+	ID    string `json:"-" validate:"required,max=100"`
+	Token string `json:"-" validate:"required"`
+}
+
 type LoginUserResponse struct {
 	User interface{} `json:"user"`
 	BackendTokens
 }
 
 type LogoutUserRequest struct {
-	ID string `json:"id" validate:"required,max=100"`
+	ID           string    `json:"-" validate:"required,max=100"`
+	TokenID      string    `json:"-" validate:"required,max=100"`
+	ExpiresAt    time.Time `json:"-"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
 }
 
 type GetUserRequest struct {
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type AuthorRepository struct {
+	Repository[entity.Author]
+	Log *logrus.Logger
+}
+
+func NewAuthorRepository(db *gorm.DB, log *logrus.Logger) *AuthorRepository {
+	return &AuthorRepository{
+		Repository: Repository[entity.Author]{DB: db},
+		Log:        log,
+	}
+}
+
+func (r *AuthorRepository) Search(ctx context.Context, request *model.SearchAuthorRequest) ([]entity.Author, int64, error) {
+	db := r.db(ctx)
+	var authors []entity.Author
+	if err := db.Scopes(r.FilterAuthor(request)).Order("name ASC, id ASC").Offset((request.Page - 1) * request.Size).Limit(request.Size).Find(&authors).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var total int64 = 0
+	if err := db.Model(new(entity.Author)).Scopes(r.FilterAuthor(request)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	return authors, total, nil
+}
+
+func (r *AuthorRepository) FilterAuthor(request *model.SearchAuthorRequest) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if name := request.Name; name != "" {
+			tx = whereContains(tx, "name", name)
+		}
+		return tx
+	}
+}
@@ -1,25 +1,102 @@
 package repository
 
 import (
+	"context"
 	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"strings"
 )
 
+// bookSortColumns whitelists the fields a book listing may be sorted by.
+var bookSortColumns = map[string]string{
+	"title":      "title",
+	"author_id":  "author_id",
+	"created_at": "created_at",
+}
+
 type BookRepository struct {
 	Repository[entity.Book]
 	Log *logrus.Logger
 }
 
-func NewBookRepository(log *logrus.Logger) *BookRepository {
+func NewBookRepository(db *gorm.DB, log *logrus.Logger) *BookRepository {
 	return &BookRepository{
-		Log: log,
+		Repository: Repository[entity.Book]{DB: db},
+		Log:        log,
+	}
+}
+
+func (r *BookRepository) Search(ctx context.Context, request *model.SearchBookRequest) ([]entity.Book, int64, error) {
+	db := r.db(ctx)
+	var books []entity.Book
+	if err := db.Scopes(r.FilterBook(request), r.IncludeBook(request.IncludeAuthor)).Order(r.SortBook(request.Sort)).Offset((request.Page - 1) * request.Size).Limit(request.Size).Find(&books).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var total int64 = 0
+	if err := db.Model(new(entity.Book)).Scopes(r.FilterBook(request)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
+}
+
+func (r *BookRepository) SearchByCursor(ctx context.Context, request *model.SearchBookRequest, cursor *model.Cursor, desc bool) ([]entity.Book, bool, error) {
+	var books []entity.Book
+	hasMore, err := r.FindByCursor(r.db(ctx).Scopes(r.FilterBook(request), r.IncludeBook(request.IncludeAuthor)), &books, cursor, desc, request.Size)
+	if err != nil {
+		return nil, false, err
+	}
+	return books, hasMore, nil
+}
+
+// FindByIdIncluding loads a book, with its author when includeAuthor is set.
+func (r *BookRepository) FindByIdIncluding(ctx context.Context, book *entity.Book, id string, includeAuthor bool) error {
+	return r.db(ctx).Scopes(r.IncludeBook(includeAuthor)).Where("id = ?", id).Take(book).Error
+}
+
+func (r *BookRepository) FilterBook(request *model.SearchBookRequest) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if title := request.Title; title != "" {
+			tx = whereContains(tx, "title", title)
+		}
+		if authorId := request.AuthorId; authorId != "" {
+			tx = tx.Where("author_id = ?", authorId)
+		}
+		return tx
 	}
 }
 
-func (r *BookRepository) FindAll(tx *gorm.DB, books *[]entity.Book) error {
-	if err := tx.Find(&books).Error; err != nil {
-		return err
+// IncludeBook preloads the author of each book when requested.
+func (r *BookRepository) IncludeBook(includeAuthor bool) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if includeAuthor {
+			tx = tx.Preload("Author")
+		}
+		return tx
+	}
+}
+
+func (r *BookRepository) CountByAuthorId(ctx context.Context, authorId string) (int64, error) {
+	var total int64
+	err := r.db(ctx).Model(new(entity.Book)).Where("author_id = ?", authorId).Count(&total).Error
+	return total, err
+}
+
+// SortBook translates a sort expression such as "title" or "-created_at" into
+// an ORDER BY clause. Unknown fields fall back to the newest books first, and
+// the id is always appended so rows with equal keys keep a stable order.
+func (r *BookRepository) SortBook(sort string) string {
+	direction := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+		sort = strings.TrimPrefix(sort, "-")
+	}
+
+	column, ok := bookSortColumns[sort]
+	if !ok {
+		column, direction = "created_at", "DESC"
 	}
-	return nil
+	return column + " " + direction + ", id " + direction
 }
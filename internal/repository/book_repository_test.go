@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/repository"
+	"slices"
+	"testing"
+	"time"
+)
+
+// createBooks stores books one second apart, except for b and c, which share
+// their creation time so the cursor has to break the tie on the id.
+func createBooks(t *testing.T, books *repository.BookRepository) {
+	t.Helper()
+	ctx := context.Background()
+
+	authors := repository.NewAuthorRepository(books.DB, newLog())
+	if err := authors.Create(ctx, &entity.Author{ID: "author", Name: "Author"}); err != nil {
+		t.Fatalf("creating the author failed: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		offset := i
+		if i > 1 {
+			offset--
+		}
+		book := &entity.Book{ID: id, Title: "Book " + id, AuthorId: "author", CreatedAt: start.Add(time.Duration(offset) * time.Second)}
+		if err := books.Create(ctx, book); err != nil {
+			t.Fatalf("creating book %s failed: %v", id, err)
+		}
+	}
+}
+
+func bookIds(books []entity.Book) []string {
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	return ids
+}
+
+func TestSearchByCursor(t *testing.T) {
+	books := repository.NewBookRepository(newDB(t), newLog())
+	createBooks(t, books)
+	ctx := context.Background()
+	request := &model.SearchBookRequest{Size: 2}
+
+	for _, test := range []struct {
+		name  string
+		desc  bool
+		pages [][]string
+	}{
+		{"oldest first", false, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{"newest first", true, [][]string{{"e", "d"}, {"c", "b"}, {"a"}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var cursor *model.Cursor
+			for i, want := range test.pages {
+				page, hasMore, err := books.SearchByCursor(ctx, request, cursor, test.desc)
+				if err != nil {
+					t.Fatalf("SearchByCursor failed: %v", err)
+				}
+				if !slices.Equal(bookIds(page), want) {
+					t.Fatalf("page %d holds %v, want %v", i+1, bookIds(page), want)
+				}
+				if last := i == len(test.pages)-1; hasMore == last {
+					t.Fatalf("page %d reports more books %t, want %t", i+1, hasMore, !last)
+				}
+				end := page[len(page)-1]
+				cursor = &model.Cursor{CreatedAt: end.CreatedAt, ID: end.ID, Desc: test.desc}
+			}
+
+			// walking back from the last page returns the page before it
+			first := test.pages[len(test.pages)-1][0]
+			page, hasMore, err := books.SearchByCursor(ctx, request, &model.Cursor{CreatedAt: createdAt(t, books, first), ID: first, Desc: test.desc, Backward: true}, test.desc)
+			if err != nil {
+				t.Fatalf("SearchByCursor backwards failed: %v", err)
+			}
+			if want := test.pages[len(test.pages)-2]; !slices.Equal(bookIds(page), want) || !hasMore {
+				t.Fatalf("backward page holds %v with more %t, want %v with more", bookIds(page), hasMore, want)
+			}
+		})
+	}
+}
+
+func TestSearchByCursorFilters(t *testing.T) {
+	books := repository.NewBookRepository(newDB(t), newLog())
+	createBooks(t, books)
+
+	page, hasMore, err := books.SearchByCursor(context.Background(), &model.SearchBookRequest{Title: "book c", Size: 2}, nil, true)
+	if err != nil {
+		t.Fatalf("SearchByCursor failed: %v", err)
+	}
+	if !slices.Equal(bookIds(page), []string{"c"}) || hasMore {
+		t.Fatalf("filtered page holds %v with more %t, want [c] alone", bookIds(page), hasMore)
+	}
+
+	page, _, err = books.SearchByCursor(context.Background(), &model.SearchBookRequest{Title: "BOOK C", Size: 2}, nil, true)
+	if err != nil {
+		t.Fatalf("SearchByCursor failed: %v", err)
+	}
+	if !slices.Equal(bookIds(page), []string{"c"}) {
+		t.Fatalf("upper case filter found %v, want [c]", bookIds(page))
+	}
+}
+
+func createdAt(t *testing.T, books *repository.BookRepository, id string) time.Time {
+	t.Helper()
+	book := new(entity.Book)
+	if err := books.FindById(context.Background(), book, id); err != nil {
+		t.Fatalf("finding book %s failed: %v", id, err)
+	}
+	return book.CreatedAt
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// DatabaseHealthChecker pings the database through the GORM pool.
+type DatabaseHealthChecker struct {
+	DB *gorm.DB
+}
+
+func (c *DatabaseHealthChecker) Name() string {
+	return "database"
+}
+
+func (c *DatabaseHealthChecker) Check(ctx context.Context) error {
+	connection, err := c.DB.DB()
+	if err != nil {
+		return err
+	}
+	return connection.PingContext(ctx)
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"slices"
+	"strings"
+)
+
+type AuthorRepository struct {
+	Repository[entity.Author]
+}
+
+func NewAuthorRepository(store *Store) *AuthorRepository {
+	return &AuthorRepository{Repository: Repository[entity.Author]{
+		Store:  store,
+		table:  func(store *Store) map[string]entity.Author { return store.authors },
+		detach: func(author *entity.Author) { author.Books = nil },
+	}}
+}
+
+func (r *AuthorRepository) Search(ctx context.Context, request *model.SearchAuthorRequest) ([]entity.Author, int64, error) {
+	var authors []entity.Author
+	var total int64
+	err := r.Store.run(ctx, func(tx *transaction) error {
+		matches := r.filter(func(row *entity.Author) bool {
+			return contains(row.Name, request.Name)
+		})
+		slices.SortStableFunc(matches, func(a entity.Author, b entity.Author) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		authors, total = page(matches, request.Page, request.Size)
+		return nil
+	})
+	return authors, total, err
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"slices"
+	"strings"
+)
+
+// bookSortFields mirrors the sort whitelist of repository.BookRepository.
+var bookSortFields = map[string]func(a *entity.Book, b *entity.Book) int{
+	"title": func(a *entity.Book, b *entity.Book) int {
+		return strings.Compare(a.Title, b.Title)
+	},
+	"author_id": func(a *entity.Book, b *entity.Book) int {
+		return strings.Compare(a.AuthorId, b.AuthorId)
+	},
+	"created_at": func(a *entity.Book, b *entity.Book) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	},
+}
+
+type BookRepository struct {
+	Repository[entity.Book]
+}
+
+func NewBookRepository(store *Store) *BookRepository {
+	return &BookRepository{Repository: Repository[entity.Book]{
+		Store:  store,
+		table:  func(store *Store) map[string]entity.Book { return store.books },
+		detach: func(book *entity.Book) { book.Author = nil },
+	}}
+}
+
+func (r *BookRepository) FindByIdIncluding(ctx context.Context, book *entity.Book, id string, includeAuthor bool) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		if err := r.find(book, id); err != nil {
+			return err
+		}
+		r.include(book, includeAuthor)
+		return nil
+	})
+}
+
+func (r *BookRepository) Search(ctx context.Context, request *model.SearchBookRequest) ([]entity.Book, int64, error) {
+	var books []entity.Book
+	var total int64
+	err := r.Store.run(ctx, func(tx *transaction) error {
+		matches := r.filter(r.filterBook(request))
+		slices.SortFunc(matches, sortBook(request.Sort))
+		books, total = page(matches, request.Page, request.Size)
+		for i := range books {
+			r.include(&books[i], request.IncludeAuthor)
+		}
+		return nil
+	})
+	return books, total, err
+}
+
+func (r *BookRepository) SearchByCursor(ctx context.Context, request *model.SearchBookRequest, cursor *model.Cursor, desc bool) ([]entity.Book, bool, error) {
+	var books []entity.Book
+	var hasMore bool
+	err := r.Store.run(ctx, func(tx *transaction) error {
+		books, hasMore = pageByCursor(r.filter(r.filterBook(request)), cursor, desc, request.Size)
+		for i := range books {
+			r.include(&books[i], request.IncludeAuthor)
+		}
+		return nil
+	})
+	return books, hasMore, err
+}
+
+func (r *BookRepository) CountByAuthorId(ctx context.Context, authorId string) (int64, error) {
+	var total int64
+	err := r.Store.run(ctx, func(tx *transaction) error {
+		total = int64(len(r.filter(func(row *entity.Book) bool {
+			return row.AuthorId == authorId
+		})))
+		return nil
+	})
+	return total, err
+}
+
+func (r *BookRepository) filterBook(request *model.SearchBookRequest) func(row *entity.Book) bool {
+	return func(row *entity.Book) bool {
+		return contains(row.Title, request.Title) && (request.AuthorId == "" || row.AuthorId == request.AuthorId)
+	}
+}
+
+// include must be called with the store locked.
+func (r *BookRepository) include(book *entity.Book, includeAuthor bool) {
+	if !includeAuthor {
+		return
+	}
+	if author, ok := r.Store.authors[book.AuthorId]; ok {
+		book.Author = &author
+	}
+}
+
+// sortBook orders like repository.BookRepository.SortBook: unknown fields
+// fall back to the newest books first, ties are broken by id.
+func sortBook(sort string) func(a entity.Book, b entity.Book) int {
+	desc := strings.HasPrefix(sort, "-")
+	compare, ok := bookSortFields[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		compare, desc = bookSortFields["created_at"], true
+	}
+	return func(a entity.Book, b entity.Book) int {
+		c := compare(&a, &b)
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	}
+}
@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+)
+
+type PermissionRepository struct {
+	Repository[entity.Permission]
+}
+
+func NewPermissionRepository(store *Store) *PermissionRepository {
+	return &PermissionRepository{Repository: Repository[entity.Permission]{
+		Store: store,
+		table: func(store *Store) map[string]entity.Permission { return store.permissions },
+	}}
+}
+
+func (r *PermissionRepository) FirstOrCreateByName(ctx context.Context, permission *entity.Permission, name string) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		permissions := r.filter(func(row *entity.Permission) bool {
+			return row.Name == name
+		})
+		if len(permissions) > 0 {
+			*permission = permissions[0]
+			return nil
+		}
+		*permission = entity.Permission{ID: uuid.NewString(), Name: name}
+		return r.create(tx, permission)
+	})
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RefreshTokenRepository struct {
+	Repository[entity.RefreshToken]
+}
+
+func NewRefreshTokenRepository(store *Store) *RefreshTokenRepository {
+	return &RefreshTokenRepository{Repository: Repository[entity.RefreshToken]{
+		Store: store,
+		table: func(store *Store) map[string]entity.RefreshToken { return store.refreshTokens },
+	}}
+}
+
+func (r *RefreshTokenRepository) FindByTokenHash(ctx context.Context, refreshToken *entity.RefreshToken, tokenHash string) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		refreshTokens := r.filter(func(row *entity.RefreshToken) bool {
+			return row.TokenHash == tokenHash
+		})
+		if len(refreshTokens) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		*refreshToken = refreshTokens[0]
+		return nil
+	})
+}
+
+func (r *RefreshTokenRepository) MarkUsed(ctx context.Context, id string, now time.Time) (bool, error) {
+	consumed := false
+	err := r.Store.write(ctx, func(tx *transaction) error {
+		row, ok := r.Store.refreshTokens[id]
+		if !ok || row.UsedAt != nil || row.RevokedAt != nil {
+			return nil
+		}
+		row.UsedAt = &now
+		put(tx, r.Store.refreshTokens, id, row)
+		consumed = true
+		return nil
+	})
+	return consumed, err
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyId string, now time.Time) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		family := r.filter(func(row *entity.RefreshToken) bool {
+			return row.FamilyId == familyId && row.RevokedAt == nil
+		})
+		for _, row := range family {
+			row.RevokedAt = &now
+			put(tx, r.Store.refreshTokens, row.ID, row)
+		}
+		return nil
+	})
+}
+
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	err := r.Store.write(ctx, func(tx *transaction) error {
+		expired := r.filter(func(row *entity.RefreshToken) bool {
+			return row.ExpiresAt.Before(now)
+		})
+		for _, row := range expired {
+			remove(tx, r.Store.refreshTokens, row.ID)
+		}
+		deleted = int64(len(expired))
+		return nil
+	})
+	return deleted, err
+}
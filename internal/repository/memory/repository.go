@@ -0,0 +1,200 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"gorm.io/gorm"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	_ usecase.UserRepository         = (*UserRepository)(nil)
+	_ usecase.RoleRepository         = (*RoleRepository)(nil)
+	_ usecase.PermissionRepository   = (*PermissionRepository)(nil)
+	_ usecase.RevokedTokenRepository = (*RevokedTokenRepository)(nil)
+	_ usecase.RefreshTokenRepository = (*RefreshTokenRepository)(nil)
+	_ usecase.BookRepository         = (*BookRepository)(nil)
+	_ usecase.AuthorRepository       = (*AuthorRepository)(nil)
+	_ usecase.TxManager              = (*TxManager)(nil)
+)
+
+// Repository is the in-memory counterpart of repository.Repository for one
+// table of the store. Entities are copied in and out, and CreatedAt and
+// UpdatedAt are maintained the way GORM's autoCreateTime and autoUpdateTime
+// would.
+type Repository[T any] struct {
+	Store *Store
+	table func(store *Store) map[string]T
+	// detach drops associations, which are kept in tables of their own
+	detach func(entity *T)
+}
+
+func (r *Repository[T]) Create(ctx context.Context, entity *T) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		return r.create(tx, entity)
+	})
+}
+
+func (r *Repository[T]) Update(ctx context.Context, entity *T) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		touch(entity, false)
+		put(tx, r.table(r.Store), entityId(entity), r.row(entity))
+		return nil
+	})
+}
+
+func (r *Repository[T]) Delete(ctx context.Context, entity *T) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		remove(tx, r.table(r.Store), entityId(entity))
+		return nil
+	})
+}
+
+func (r *Repository[T]) CountById(ctx context.Context, id any) (int64, error) {
+	var total int64
+	err := r.Store.run(ctx, func(tx *transaction) error {
+		if _, ok := r.table(r.Store)[fmt.Sprint(id)]; ok {
+			total = 1
+		}
+		return nil
+	})
+	return total, err
+}
+
+func (r *Repository[T]) FindById(ctx context.Context, entity *T, id any) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		return r.find(entity, fmt.Sprint(id))
+	})
+}
+
+// create must be called with the store locked.
+func (r *Repository[T]) create(tx *transaction, entity *T) error {
+	rows := r.table(r.Store)
+	id := entityId(entity)
+	if _, ok := rows[id]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	touch(entity, true)
+	put(tx, rows, id, r.row(entity))
+	return nil
+}
+
+// find must be called with the store locked.
+func (r *Repository[T]) find(entity *T, id string) error {
+	row, ok := r.table(r.Store)[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*entity = row
+	return nil
+}
+
+// filter returns the rows matching keep ordered by id, like a query without
+// an ORDER BY on a clustered primary key. It must be called with the store
+// locked.
+func (r *Repository[T]) filter(keep func(row *T) bool) []T {
+	rows := r.table(r.Store)
+	ids := make([]string, 0, len(rows))
+	for id := range rows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	matches := make([]T, 0, len(ids))
+	for _, id := range ids {
+		row := rows[id]
+		if keep(&row) {
+			matches = append(matches, row)
+		}
+	}
+	return matches
+}
+
+func (r *Repository[T]) row(entity *T) T {
+	row := *entity
+	if r.detach != nil {
+		r.detach(&row)
+	}
+	return row
+}
+
+func entityId(entity any) string {
+	return reflect.ValueOf(entity).Elem().FieldByName("ID").String()
+}
+
+// touch sets the timestamps of entity at the millisecond precision the
+// tables store them with.
+func touch(entity any, create bool) {
+	value := reflect.ValueOf(entity).Elem()
+	now := time.Now().Truncate(time.Millisecond)
+	if field := value.FieldByName("CreatedAt"); field.IsValid() && field.Interface().(time.Time).IsZero() {
+		field.Set(reflect.ValueOf(now))
+	}
+	if field := value.FieldByName("UpdatedAt"); field.IsValid() && (!create || field.Interface().(time.Time).IsZero()) {
+		field.Set(reflect.ValueOf(now))
+	}
+}
+
+func createdAt(entity any) time.Time {
+	return reflect.ValueOf(entity).Elem().FieldByName("CreatedAt").Interface().(time.Time)
+}
+
+// compareCreated orders entities by created_at, then id.
+func compareCreated(a any, b any) int {
+	if c := createdAt(a).Compare(createdAt(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(entityId(a), entityId(b))
+}
+
+// pageByCursor pages rows exactly like repository.Repository.FindByCursor.
+func pageByCursor[T any](rows []T, cursor *model.Cursor, desc bool, limit int) ([]T, bool) {
+	backward := cursor != nil && cursor.Backward
+	descending := desc != backward
+
+	if cursor != nil {
+		position := &struct {
+			ID        string
+			CreatedAt time.Time
+		}{ID: cursor.ID, CreatedAt: cursor.CreatedAt}
+		rows = slices.DeleteFunc(rows, func(row T) bool {
+			c := compareCreated(&row, position)
+			return (descending && c >= 0) || (!descending && c <= 0)
+		})
+	}
+	slices.SortFunc(rows, func(a T, b T) int {
+		if descending {
+			return compareCreated(&b, &a)
+		}
+		return compareCreated(&a, &b)
+	})
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if backward {
+		slices.Reverse(rows)
+	}
+	return rows, hasMore
+}
+
+// page applies offset pagination and returns the total before paging.
+func page[T any](rows []T, pageNumber int, size int) ([]T, int64) {
+	total := int64(len(rows))
+	start := min((pageNumber-1)*size, len(rows))
+	end := min(start+size, len(rows))
+	return rows[start:end], total
+}
+
+// contains mimics the LOWER(column) LIKE LOWER('%substr%') the GORM
+// repositories search with.
+func contains(value string, substr string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(substr))
+}
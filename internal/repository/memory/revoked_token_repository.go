@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"time"
+)
+
+type RevokedTokenRepository struct {
+	Repository[entity.RevokedToken]
+}
+
+func NewRevokedTokenRepository(store *Store) *RevokedTokenRepository {
+	return &RevokedTokenRepository{Repository: Repository[entity.RevokedToken]{
+		Store: store,
+		table: func(store *Store) map[string]entity.RevokedToken { return store.revokedTokens },
+	}}
+}
+
+func (r *RevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	err := r.Store.write(ctx, func(tx *transaction) error {
+		expired := r.filter(func(row *entity.RevokedToken) bool {
+			return row.ExpiresAt.Before(now)
+		})
+		for _, row := range expired {
+			remove(tx, r.Store.revokedTokens, row.ID)
+		}
+		deleted = int64(len(expired))
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"slices"
+	"strings"
+)
+
+type RoleRepository struct {
+	Repository[entity.Role]
+}
+
+func NewRoleRepository(store *Store) *RoleRepository {
+	return &RoleRepository{Repository: Repository[entity.Role]{
+		Store:  store,
+		table:  func(store *Store) map[string]entity.Role { return store.roles },
+		detach: func(role *entity.Role) { role.Permissions = nil },
+	}}
+}
+
+func (r *RoleRepository) FindAll(ctx context.Context, roles *[]entity.Role) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		*roles = r.filter(func(row *entity.Role) bool {
+			return true
+		})
+		for i := range *roles {
+			(*roles)[i].Permissions = r.Store.permissionsOf((*roles)[i].ID)
+		}
+		sortRolesByName(*roles)
+		return nil
+	})
+}
+
+func (r *RoleRepository) FindByNames(ctx context.Context, roles *[]entity.Role, names []string) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		*roles = r.filter(func(row *entity.Role) bool {
+			return slices.Contains(names, row.Name)
+		})
+		return nil
+	})
+}
+
+func (r *RoleRepository) FindByUserId(ctx context.Context, roles *[]entity.Role, userId string) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		*roles = r.Store.rolesOf(userId, true)
+		return nil
+	})
+}
+
+func (r *RoleRepository) FirstOrCreateByName(ctx context.Context, role *entity.Role, name string) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		roles := r.filter(func(row *entity.Role) bool {
+			return row.Name == name
+		})
+		if len(roles) > 0 {
+			*role = roles[0]
+			return nil
+		}
+		*role = entity.Role{ID: uuid.NewString(), Name: name}
+		return r.create(tx, role)
+	})
+}
+
+func (r *RoleRepository) ReplacePermissions(ctx context.Context, role *entity.Role, permissions []entity.Permission) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		ids := make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			ids = append(ids, permission.ID)
+		}
+		put(tx, r.Store.rolePermissions, role.ID, ids)
+		role.Permissions = permissions
+		return nil
+	})
+}
+
+// rolesOf returns the roles granted to a user, optionally with their
+// permissions. It must be called with the store locked.
+func (s *Store) rolesOf(userId string, withPermissions bool) []entity.Role {
+	roles := make([]entity.Role, 0, len(s.userRoles[userId]))
+	for _, id := range s.userRoles[userId] {
+		role, ok := s.roles[id]
+		if !ok {
+			continue
+		}
+		if withPermissions {
+			role.Permissions = s.permissionsOf(role.ID)
+		}
+		roles = append(roles, role)
+	}
+	sortRolesByName(roles)
+	return roles
+}
+
+// permissionsOf must be called with the store locked.
+func (s *Store) permissionsOf(roleId string) []entity.Permission {
+	permissions := make([]entity.Permission, 0, len(s.rolePermissions[roleId]))
+	for _, id := range s.rolePermissions[roleId] {
+		if permission, ok := s.permissions[id]; ok {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
+func sortRolesByName(roles []entity.Role) {
+	slices.SortFunc(roles, func(a entity.Role, b entity.Role) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"sync"
+)
+
+var errReadOnly = errors.New("memory store: write in a read-only transaction")
+
+// Store keeps the tables of the in-memory repositories. A transaction begun
+// by a TxManager of the store holds the store lock until it ends, so
+// concurrent use cases are serialized, and a rollback undoes every write the
+// transaction made.
+type Store struct {
+	mutex           sync.Mutex
+	users           map[string]entity.User
+	userRoles       map[string][]string
+	roles           map[string]entity.Role
+	rolePermissions map[string][]string
+	permissions     map[string]entity.Permission
+	revokedTokens   map[string]entity.RevokedToken
+	refreshTokens   map[string]entity.RefreshToken
+	authors         map[string]entity.Author
+	books           map[string]entity.Book
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:           make(map[string]entity.User),
+		userRoles:       make(map[string][]string),
+		roles:           make(map[string]entity.Role),
+		rolePermissions: make(map[string][]string),
+		permissions:     make(map[string]entity.Permission),
+		revokedTokens:   make(map[string]entity.RevokedToken),
+		refreshTokens:   make(map[string]entity.RefreshToken),
+		authors:         make(map[string]entity.Author),
+		books:           make(map[string]entity.Book),
+	}
+}
+
+// run calls fn with the store locked. Within a transaction of the store the
+// lock is already held, and fn's undo functions are recorded for a rollback.
+func (s *Store) run(ctx context.Context, fn func(tx *transaction) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*transaction); ok && tx.store == s {
+		return fn(tx)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return fn(nil)
+}
+
+// write is run for functions that change the store, which a read-only
+// transaction rejects.
+func (s *Store) write(ctx context.Context, fn func(tx *transaction) error) error {
+	return s.run(ctx, func(tx *transaction) error {
+		if tx != nil && tx.readOnly {
+			return errReadOnly
+		}
+		return fn(tx)
+	})
+}
+
+// put stores value under id, recording how to restore the previous row.
+func put[T any](tx *transaction, rows map[string]T, id string, value T) {
+	previous, existed := rows[id]
+	tx.record(func() {
+		if existed {
+			rows[id] = previous
+		} else {
+			delete(rows, id)
+		}
+	})
+	rows[id] = value
+}
+
+// remove deletes the row under id, recording how to restore it.
+func remove[T any](tx *transaction, rows map[string]T, id string) {
+	previous, existed := rows[id]
+	if !existed {
+		return
+	}
+	tx.record(func() {
+		rows[id] = previous
+	})
+	delete(rows, id)
+}
+
+type transaction struct {
+	store    *Store
+	readOnly bool
+	undo     []func()
+	done     bool
+}
+
+// record keeps undo for a rollback, outside of a transaction tx is nil and
+// writes are final.
+func (t *transaction) record(undo func()) {
+	if t != nil {
+		t.undo = append(t.undo, undo)
+	}
+}
+
+// rollbackTo undoes the writes recorded after the first savepoint ones.
+func (t *transaction) rollbackTo(savepoint int) {
+	for i := len(t.undo) - 1; i >= savepoint; i-- {
+		t.undo[i]()
+	}
+	t.undo = t.undo[:savepoint]
+}
+
+func (t *transaction) commit() {
+	t.done = true
+	t.undo = nil
+	t.store.mutex.Unlock()
+}
+
+// rollback ends the transaction unless it was committed already.
+func (t *transaction) rollback() {
+	if t.done {
+		return
+	}
+	t.rollbackTo(0)
+	t.done = true
+	t.store.mutex.Unlock()
+}
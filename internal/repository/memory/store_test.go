@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"gorm.io/gorm"
+	"testing"
+)
+
+var errAbort = errors.New("abort")
+
+func TestTransactionRollback(t *testing.T) {
+	store := NewStore()
+	txManager := NewTxManager(store)
+	repository := NewAuthorRepository(store)
+	ctx := context.Background()
+
+	kept := &entity.Author{ID: "kept", Name: "Kept"}
+	if err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		return repository.Create(ctx, kept)
+	}); err != nil {
+		t.Fatalf("WithinTransaction failed: %v", err)
+	}
+
+	err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		kept.Name = "Renamed"
+		if err := repository.Update(ctx, kept); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if err := repository.Create(ctx, &entity.Author{ID: "discarded", Name: "Discarded"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTransaction returned %v, want %v", err, errAbort)
+	}
+
+	found := new(entity.Author)
+	if err := repository.FindById(ctx, found, "kept"); err != nil || found.Name != "Kept" {
+		t.Fatalf("rolled back update is visible: %+v, %v", found, err)
+	}
+	if total, _ := repository.CountById(ctx, "discarded"); total != 0 {
+		t.Fatalf("rolled back insert is visible")
+	}
+}
+
+func TestNestedTransactionRollback(t *testing.T) {
+	store := NewStore()
+	txManager := NewTxManager(store)
+	repository := NewAuthorRepository(store)
+	ctx := context.Background()
+
+	err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := repository.Create(ctx, &entity.Author{ID: "outer", Name: "Outer"}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := repository.Create(ctx, &entity.Author{ID: "inner", Name: "Inner"}); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("nested WithinTransaction returned %v, want %v", err, errAbort)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction failed: %v", err)
+	}
+
+	if total, _ := repository.CountById(ctx, "outer"); total != 1 {
+		t.Fatalf("outer insert was rolled back with the nested transaction")
+	}
+	if total, _ := repository.CountById(ctx, "inner"); total != 0 {
+		t.Fatalf("nested insert survived its rollback")
+	}
+}
+
+func TestReadOnlyTransaction(t *testing.T) {
+	store := NewStore()
+	txManager := NewTxManager(store)
+	repository := NewAuthorRepository(store)
+	ctx := context.Background()
+
+	err := txManager.WithinReadOnlyTransaction(ctx, func(ctx context.Context) error {
+		return repository.Create(ctx, &entity.Author{ID: "written", Name: "Written"})
+	})
+	if !errors.Is(err, errReadOnly) {
+		t.Fatalf("write in a read-only transaction returned %v, want %v", err, errReadOnly)
+	}
+	if total, _ := repository.CountById(ctx, "written"); total != 0 {
+		t.Fatalf("write in a read-only transaction is visible")
+	}
+}
+
+func TestUniqueUserEmail(t *testing.T) {
+	store := NewStore()
+	repository := NewUserRepository(store)
+	ctx := context.Background()
+
+	if err := repository.Create(ctx, &entity.User{ID: "first", Email: "taken@example.com"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second := &entity.User{ID: "second", Email: "free@example.com"}
+	if err := repository.Create(ctx, second); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if err := repository.Create(ctx, &entity.User{ID: "third", Email: "taken@example.com"}); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Create with a taken email returned %v, want %v", err, gorm.ErrDuplicatedKey)
+	}
+	second.Email = "taken@example.com"
+	if err := repository.Update(ctx, second); !errors.Is(err, gorm.ErrDuplicatedKey) {
+		t.Fatalf("Update to a taken email returned %v, want %v", err, gorm.ErrDuplicatedKey)
+	}
+	second.Email = "free@example.com"
+	if err := repository.Update(ctx, second); err != nil {
+		t.Fatalf("Update keeping its own email failed: %v", err)
+	}
+}
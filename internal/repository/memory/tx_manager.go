@@ -0,0 +1,44 @@
+package memory
+
+import "context"
+
+type txKey struct{}
+
+// TxManager is the in-memory counterpart of repository.TxManager. A nested
+// transaction rolls back to the writes made before it began, like a savepoint.
+type TxManager struct {
+	Store *Store
+}
+
+func NewTxManager(store *Store) *TxManager {
+	return &TxManager{Store: store}
+}
+
+func (m *TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.within(ctx, false, fn)
+}
+
+func (m *TxManager) WithinReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.within(ctx, true, fn)
+}
+
+func (m *TxManager) within(ctx context.Context, readOnly bool, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*transaction); ok && tx.store == m.Store {
+		savepoint := len(tx.undo)
+		if err := fn(ctx); err != nil {
+			tx.rollbackTo(savepoint)
+			return err
+		}
+		return nil
+	}
+
+	m.Store.mutex.Lock()
+	tx := &transaction{store: m.Store, readOnly: readOnly}
+	defer tx.rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	tx.commit()
+	return nil
+}
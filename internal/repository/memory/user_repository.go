@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"gorm.io/gorm"
+	"slices"
+)
+
+type UserRepository struct {
+	Repository[entity.User]
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{Repository: Repository[entity.User]{
+		Store:  store,
+		table:  func(store *Store) map[string]entity.User { return store.users },
+		detach: func(user *entity.User) { user.Roles = nil },
+	}}
+}
+
+// Create also grants the roles set on the user, as GORM saves the
+// association along with it.
+func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		if r.emailTaken(user) {
+			return gorm.ErrDuplicatedKey
+		}
+		if err := r.create(tx, user); err != nil {
+			return err
+		}
+		if len(user.Roles) > 0 {
+			put(tx, r.Store.userRoles, user.ID, roleIds(user.Roles))
+		}
+		return nil
+	})
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		if r.emailTaken(user) {
+			return gorm.ErrDuplicatedKey
+		}
+		touch(user, false)
+		put(tx, r.Store.users, user.ID, r.row(user))
+		return nil
+	})
+}
+
+// emailTaken enforces the unique email index of the users table. It must be
+// called with the store locked.
+func (r *UserRepository) emailTaken(user *entity.User) bool {
+	return len(r.filter(func(row *entity.User) bool {
+		return row.Email == user.Email && row.ID != user.ID
+	})) > 0
+}
+
+func (r *UserRepository) FindByIdWithRoles(ctx context.Context, user *entity.User, id string) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		if err := r.find(user, id); err != nil {
+			return err
+		}
+		user.Roles = r.Store.rolesOf(user.ID, false)
+		return nil
+	})
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	user := new(entity.User)
+	err := r.Store.run(ctx, func(tx *transaction) error {
+		users := r.filter(func(row *entity.User) bool {
+			return row.Email == email
+		})
+		if len(users) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		*user = users[0]
+		return nil
+	})
+	return user, err
+}
+
+func (r *UserRepository) FindByEmails(ctx context.Context, users *[]entity.User, emails []string) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		*users = r.filter(func(row *entity.User) bool {
+			return slices.Contains(emails, row.Email)
+		})
+		return nil
+	})
+}
+
+func (r *UserRepository) FindWithoutRoles(ctx context.Context, users *[]entity.User) error {
+	return r.Store.run(ctx, func(tx *transaction) error {
+		*users = r.filter(func(row *entity.User) bool {
+			return len(r.Store.userRoles[row.ID]) == 0
+		})
+		return nil
+	})
+}
+
+func (r *UserRepository) ReplaceRoles(ctx context.Context, user *entity.User, roles []entity.Role) error {
+	return r.Store.write(ctx, func(tx *transaction) error {
+		put(tx, r.Store.userRoles, user.ID, roleIds(roles))
+		user.Roles = roles
+		return nil
+	})
+}
+
+func roleIds(roles []entity.Role) []string {
+	ids := make([]string, 0, len(roles))
+	for _, role := range roles {
+		ids = append(ids, role.ID)
+	}
+	return ids
+}
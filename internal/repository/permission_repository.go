@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PermissionRepository struct {
+	Repository[entity.Permission]
+	Log *logrus.Logger
+}
+
+func NewPermissionRepository(db *gorm.DB, log *logrus.Logger) *PermissionRepository {
+	return &PermissionRepository{
+		Repository: Repository[entity.Permission]{DB: db},
+		Log:        log,
+	}
+}
+
+func (r *PermissionRepository) FirstOrCreateByName(ctx context.Context, permission *entity.Permission, name string) error {
+	return r.db(ctx).Where(entity.Permission{Name: name}).Attrs(entity.Permission{ID: uuid.NewString()}).FirstOrCreate(permission).Error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RefreshTokenRepository struct {
+	Repository[entity.RefreshToken]
+	Log *logrus.Logger
+}
+
+func NewRefreshTokenRepository(db *gorm.DB, log *logrus.Logger) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		Repository: Repository[entity.RefreshToken]{DB: db},
+		Log:        log,
+	}
+}
+
+func (r *RefreshTokenRepository) FindByTokenHash(ctx context.Context, refreshToken *entity.RefreshToken, tokenHash string) error {
+	return r.db(ctx).Where("token_hash = ?", tokenHash).Take(refreshToken).Error
+}
+
+// MarkUsed consumes the token, reporting false when it was already used or
+// revoked so that concurrent refreshes with the same token cannot both win.
+func (r *RefreshTokenRepository) MarkUsed(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := r.db(ctx).Model(new(entity.RefreshToken)).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyId string, now time.Time) error {
+	return r.db(ctx).Model(new(entity.RefreshToken)).
+		Where("family_id = ? AND revoked_at IS NULL", familyId).
+		Update("revoked_at", now).Error
+}
+
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db(ctx).Where("expires_at < ?", now).Delete(new(entity.RefreshToken))
+	return result.RowsAffected, result.Error
+}
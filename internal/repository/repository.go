@@ -1,29 +1,87 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"gorm.io/gorm"
+	"slices"
+	"strings"
+)
 
+// Repository runs its queries in the transaction of the context they are
+// given, see TxManager, and on DB outside of one.
 type Repository[T any] struct {
 	DB *gorm.DB
 }
 
-func (r *Repository[T]) Create(db *gorm.DB, entity *T) error {
-	return db.Create(entity).Error
+func (r *Repository[T]) db(ctx context.Context) *gorm.DB {
+	return transaction(ctx, r.DB)
 }
 
-func (r *Repository[T]) Update(db *gorm.DB, entity *T) error {
-	return db.Save(entity).Error
+func (r *Repository[T]) Create(ctx context.Context, entity *T) error {
+	return r.db(ctx).Create(entity).Error
 }
 
-func (r *Repository[T]) Delete(db *gorm.DB, entity *T) error {
-	return db.Delete(entity).Error
+func (r *Repository[T]) Update(ctx context.Context, entity *T) error {
+	return r.db(ctx).Save(entity).Error
 }
 
-func (r *Repository[T]) CountById(db *gorm.DB, id any) (int64, error) {
+func (r *Repository[T]) Delete(ctx context.Context, entity *T) error {
+	return r.db(ctx).Delete(entity).Error
+}
+
+func (r *Repository[T]) CountById(ctx context.Context, id any) (int64, error) {
 	var total int64
-	err := db.Model(new(T)).Where("id = ?", id).Count(&total).Error
+	err := r.db(ctx).Model(new(T)).Where("id = ?", id).Count(&total).Error
 	return total, err
 }
 
-func (r *Repository[T]) FindById(db *gorm.DB, entity *T, id any) error {
-	return db.Where("id = ?", id).Take(entity).Error
+func (r *Repository[T]) FindById(ctx context.Context, entity *T, id any) error {
+	return r.db(ctx).Where("id = ?", id).Take(entity).Error
+}
+
+// FindByCursor loads up to limit entities past the cursor, ordered by
+// created_at and id so the position stays stable under concurrent inserts.
+// A nil cursor starts from the beginning of the listing. It reports whether
+// more entities exist beyond the returned page.
+func (r *Repository[T]) FindByCursor(db *gorm.DB, entities *[]T, cursor *model.Cursor, desc bool, limit int) (bool, error) {
+	backward := cursor != nil && cursor.Backward
+
+	// Walking backwards flips the order; the page is reversed afterwards.
+	operator, order := ">", "ASC"
+	if desc != backward {
+		operator, order = "<", "DESC"
+	}
+	if cursor != nil {
+		db = db.Where("(created_at "+operator+" ? OR (created_at = ? AND id "+operator+" ?))", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	if err := db.Order("created_at " + order + ", id " + order).Limit(limit + 1).Find(entities).Error; err != nil {
+		return false, err
+	}
+
+	hasMore := len(*entities) > limit
+	if hasMore {
+		*entities = (*entities)[:limit]
+	}
+	if backward {
+		slices.Reverse(*entities)
+	}
+	return hasMore, nil
+}
+
+// likeEscaper makes user input match literally in a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// whereContains keeps the rows whose column contains value regardless of
+// case, matching the wildcards % and _ in value literally. LIKE compares
+// case-sensitively on Postgres, so both sides are lowered on every database.
+// MySQL already escapes LIKE patterns with a backslash and would read '\' as
+// an unterminated string.
+func whereContains(db *gorm.DB, column string, value string) *gorm.DB {
+	condition := "LOWER(" + column + ") LIKE LOWER(?)"
+	if db.Dialector.Name() != "mysql" {
+		condition += ` ESCAPE '\'`
+	}
+	return db.Where(condition, "%"+likeEscaper.Replace(value)+"%")
 }
@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"github.com/manikandareas/go-clean-architecture/db/migrations"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"io/fs"
+	"net/url"
+	"testing"
+)
+
+// newDB opens an in-memory SQLite database of its own for the test, with the
+// embedded migrations applied the way cmd/migrate would.
+func newDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_pragma=foreign_keys(1)", url.QueryEscape(t.Name()))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard, TranslateError: true})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	connection, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = connection.Close() })
+
+	files, err := fs.Glob(migrations.FS, "sqlite/*.up.sql")
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+	for _, file := range files {
+		migration, err := fs.ReadFile(migrations.FS, file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		if err := db.Exec(string(migration)).Error; err != nil {
+			t.Fatalf("failed to apply %s: %v", file, err)
+		}
+	}
+	return db
+}
+
+func newLog() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
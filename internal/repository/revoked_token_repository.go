@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RevokedTokenRepository struct {
+	Repository[entity.RevokedToken]
+	Log *logrus.Logger
+}
+
+func NewRevokedTokenRepository(db *gorm.DB, log *logrus.Logger) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		Repository: Repository[entity.RevokedToken]{DB: db},
+		Log:        log,
+	}
+}
+
+// DeleteExpired removes denylist entries whose tokens have already expired.
+func (r *RevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db(ctx).Where("expires_at < ?", now).Delete(new(entity.RevokedToken))
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	Repository[entity.Role]
+	Log *logrus.Logger
+}
+
+func NewRoleRepository(db *gorm.DB, log *logrus.Logger) *RoleRepository {
+	return &RoleRepository{
+		Repository: Repository[entity.Role]{DB: db},
+		Log:        log,
+	}
+}
+
+func (r *RoleRepository) FindAll(ctx context.Context, roles *[]entity.Role) error {
+	return r.db(ctx).Preload("Permissions").Order("name ASC").Find(roles).Error
+}
+
+func (r *RoleRepository) FindByNames(ctx context.Context, roles *[]entity.Role, names []string) error {
+	return r.db(ctx).Where("name IN ?", names).Find(roles).Error
+}
+
+// FindByUserId loads the roles granted to a user together with their permissions.
+func (r *RoleRepository) FindByUserId(ctx context.Context, roles *[]entity.Role, userId string) error {
+	return r.db(ctx).Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userId).
+		Find(roles).Error
+}
+
+func (r *RoleRepository) FirstOrCreateByName(ctx context.Context, role *entity.Role, name string) error {
+	return r.db(ctx).Where(entity.Role{Name: name}).Attrs(entity.Role{ID: uuid.NewString()}).FirstOrCreate(role).Error
+}
+
+func (r *RoleRepository) ReplacePermissions(ctx context.Context, role *entity.Role, permissions []entity.Permission) error {
+	return r.db(ctx).Model(role).Association("Permissions").Replace(permissions)
+}
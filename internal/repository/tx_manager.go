@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// TxManager runs a function in a transaction stored in the context it passes
+// on, where the repositories pick it up. Called within a transaction it opens
+// a savepoint instead, so a failing function only undoes its own writes and
+// keeps the access mode of the outermost transaction.
+type TxManager struct {
+	DB  *gorm.DB
+	Log *logrus.Logger
+}
+
+func NewTxManager(db *gorm.DB, log *logrus.Logger) *TxManager {
+	return &TxManager{DB: db, Log: log}
+}
+
+func (m *TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.within(ctx, nil, fn)
+}
+
+// WithinReadOnlyTransaction lets the database reject writes and, where it
+// supports them, use read-only snapshots. SQLite ignores the mode.
+func (m *TxManager) WithinReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.within(ctx, &sql.TxOptions{ReadOnly: true}, fn)
+}
+
+func (m *TxManager) within(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		// GORM turns a transaction begun on a transaction into a savepoint.
+		return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, tx))
+		})
+	}
+
+	tx := m.DB.WithContext(ctx).Begin(opts)
+	if err := tx.Error; err != nil {
+		pkg.Logger(ctx, m.Log).WithError(err).Error("failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		pkg.Logger(ctx, m.Log).WithError(err).Error("failed to commit transaction")
+		return err
+	}
+	return nil
+}
+
+// transaction returns the transaction ctx runs in or, outside of one, db.
+// Either way the statement is bound to ctx for tracing and logging.
+func transaction(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		db = tx
+	}
+	return db.WithContext(ctx)
+}
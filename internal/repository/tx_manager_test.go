@@ -0,0 +1,148 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/repository"
+	"gorm.io/gorm"
+	"testing"
+)
+
+var errAbort = errors.New("abort")
+
+// beginRecorder remembers the options of every transaction begun on the pool.
+type beginRecorder struct {
+	*sql.DB
+	opts []*sql.TxOptions
+}
+
+func (r *beginRecorder) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	r.opts = append(r.opts, opts)
+	return r.DB.BeginTx(ctx, opts)
+}
+
+func createAuthor(t *testing.T, ctx context.Context, authors *repository.AuthorRepository, id string) {
+	t.Helper()
+	if err := authors.Create(ctx, &entity.Author{ID: id, Name: "Author " + id}); err != nil {
+		t.Fatalf("creating author %s failed: %v", id, err)
+	}
+}
+
+func assertAuthor(t *testing.T, authors *repository.AuthorRepository, id string, exists bool) {
+	t.Helper()
+	err := authors.FindById(context.Background(), new(entity.Author), id)
+	if exists && err != nil {
+		t.Fatalf("author %s is missing: %v", id, err)
+	}
+	if !exists && !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("author %s exists or failed to load: %v", id, err)
+	}
+}
+
+// assertAuthorIn checks that the transaction in ctx sees the author.
+func assertAuthorIn(t *testing.T, ctx context.Context, authors *repository.AuthorRepository, id string) {
+	t.Helper()
+	if err := authors.FindById(ctx, new(entity.Author), id); err != nil {
+		t.Fatalf("author %s is not visible in the transaction: %v", id, err)
+	}
+}
+
+func TestWithinTransaction(t *testing.T) {
+	db := newDB(t)
+	txManager := repository.NewTxManager(db, newLog())
+	authors := repository.NewAuthorRepository(db, newLog())
+
+	// the repository writes through the transaction in the context, so a
+	// failing function takes the row with it
+	err := txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		createAuthor(t, ctx, authors, "rolled-back")
+		assertAuthorIn(t, ctx, authors, "rolled-back")
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTransaction returned %v, want %v", err, errAbort)
+	}
+	assertAuthor(t, authors, "rolled-back", false)
+
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		createAuthor(t, ctx, authors, "committed")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction failed: %v", err)
+	}
+	assertAuthor(t, authors, "committed", true)
+}
+
+func TestWithinTransactionNested(t *testing.T) {
+	db := newDB(t)
+	txManager := repository.NewTxManager(db, newLog())
+	authors := repository.NewAuthorRepository(db, newLog())
+
+	// a failing nested call only rolls back to its savepoint
+	err := txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		createAuthor(t, ctx, authors, "outer")
+		err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			createAuthor(t, ctx, authors, "inner")
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("nested WithinTransaction returned %v, want %v", err, errAbort)
+		}
+		assertAuthorIn(t, ctx, authors, "outer")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTransaction failed: %v", err)
+	}
+	assertAuthor(t, authors, "outer", true)
+	assertAuthor(t, authors, "inner", false)
+
+	// a nested call that succeeds still goes down with the outer transaction
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			createAuthor(t, ctx, authors, "released")
+			return nil
+		}); err != nil {
+			t.Fatalf("nested WithinTransaction failed: %v", err)
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithinTransaction returned %v, want %v", err, errAbort)
+	}
+	assertAuthor(t, authors, "released", false)
+}
+
+func TestWithinReadOnlyTransaction(t *testing.T) {
+	db := newDB(t)
+	connection, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	recorder := &beginRecorder{DB: connection}
+	db.Statement.ConnPool = recorder
+	txManager := repository.NewTxManager(db, newLog())
+	authors := repository.NewAuthorRepository(db, newLog())
+	createAuthor(t, context.Background(), authors, "existing")
+	// GORM wraps the insert in a transaction of its own
+	recorder.opts = nil
+
+	// a nested call joins the read-only transaction instead of beginning a
+	// writable one of its own
+	err = txManager.WithinReadOnlyTransaction(context.Background(), func(ctx context.Context) error {
+		assertAuthorIn(t, ctx, authors, "existing")
+		return txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			assertAuthorIn(t, ctx, authors, "existing")
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithinReadOnlyTransaction failed: %v", err)
+	}
+	if len(recorder.opts) != 1 || recorder.opts[0] == nil || !recorder.opts[0].ReadOnly {
+		t.Fatalf("began transactions with %v, want a single read-only one", recorder.opts)
+	}
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/manikandareas/go-clean-architecture/internal/entity"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -11,16 +12,37 @@ type UserRepository struct {
 	Log *logrus.Logger
 }
 
-func NewUserRepository(log *logrus.Logger) *UserRepository {
-	return &UserRepository{Log: log}
+func NewUserRepository(db *gorm.DB, log *logrus.Logger) *UserRepository {
+	return &UserRepository{
+		Repository: Repository[entity.User]{DB: db},
+		Log:        log,
+	}
 }
 
-func (r *UserRepository) FindByToken(db *gorm.DB, user *entity.User, token string) error {
-	return db.Where("token = ?", token).First(user).Error
+func (r *UserRepository) FindByToken(ctx context.Context, user *entity.User, token string) error {
+	return r.db(ctx).Where("token = ?", token).First(user).Error
 }
 
-func (r *UserRepository) FindByEmail(db *gorm.DB, email string) (*entity.User, error) {
+// FindByIdWithRoles loads a user together with the roles granted to them.
+func (r *UserRepository) FindByIdWithRoles(ctx context.Context, user *entity.User, id string) error {
+	return r.db(ctx).Preload("Roles").Where("id = ?", id).Take(user).Error
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
 	user := new(entity.User)
-	err := db.Where("email = ?", email).First(user).Error
+	err := r.db(ctx).Where("email = ?", email).First(user).Error
 	return user, err
 }
+
+func (r *UserRepository) ReplaceRoles(ctx context.Context, user *entity.User, roles []entity.Role) error {
+	return r.db(ctx).Model(user).Association("Roles").Replace(roles)
+}
+
+// FindWithoutRoles loads users that have not been granted any role yet.
+func (r *UserRepository) FindWithoutRoles(ctx context.Context, users *[]entity.User) error {
+	return r.db(ctx).Where("NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id)").Find(users).Error
+}
+
+func (r *UserRepository) FindByEmails(ctx context.Context, users *[]entity.User, emails []string) error {
+	return r.db(ctx).Where("email IN ?", emails).Find(users).Error
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/model/converter"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+)
+
+type AuthorUseCase struct {
+	TxManager        TxManager
+	Log              *logrus.Logger
+	Validate         *validator.Validate
+	AuthorRepository AuthorRepository
+	BookRepository   BookRepository
+}
+
+func NewAuthorUseCase(txManager TxManager, log *logrus.Logger, validate *validator.Validate, authorRepository AuthorRepository, bookRepository BookRepository) *AuthorUseCase {
+	return &AuthorUseCase{
+		TxManager:        txManager,
+		Log:              log,
+		Validate:         validate,
+		AuthorRepository: authorRepository,
+		BookRepository:   bookRepository,
+	}
+}
+
+func (c *AuthorUseCase) Search(ctx context.Context, request *model.SearchAuthorRequest) ([]model.AuthorResponse, int64, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, 0, model.NewValidationError(err)
+	}
+
+	var authors []entity.Author
+	var total int64
+	err := c.TxManager.WithinReadOnlyTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if authors, total, err = c.AuthorRepository.Search(ctx, request); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to search authors")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return converter.AuthorsToResponse(&authors), total, nil
+}
+
+func (c *AuthorUseCase) Create(ctx context.Context, request *model.AuthorRequest) (*model.AuthorResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, model.NewValidationError(err)
+	}
+
+	author := &entity.Author{
+		ID:   uuid.NewString(),
+		Name: request.Name,
+		Bio:  request.Bio,
+	}
+	if err := c.AuthorRepository.Create(ctx, author); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to create author")
+		return nil, fiber.ErrInternalServerError
+	}
+	return converter.AuthorToResponse(author), nil
+}
+
+func (c *AuthorUseCase) Get(ctx context.Context, request *model.GetAuthorRequest) (*model.AuthorResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, model.NewValidationError(err)
+	}
+
+	author := new(entity.Author)
+	if err := c.AuthorRepository.FindById(ctx, author, request.ID); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find author")
+		return nil, fiber.ErrNotFound
+	}
+	return converter.AuthorToResponse(author), nil
+}
+
+func (c *AuthorUseCase) Update(ctx context.Context, request *model.UpdateAuthorRequest) (*model.AuthorResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, model.NewValidationError(err)
+	}
+
+	author := new(entity.Author)
+	err := c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.AuthorRepository.FindById(ctx, author, request.ID); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find author")
+			return fiber.ErrNotFound
+		}
+
+		if request.Name != "" {
+			author.Name = request.Name
+		}
+		if request.Bio != "" {
+			author.Bio = request.Bio
+		}
+
+		if err := c.AuthorRepository.Update(ctx, author); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to update author")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return converter.AuthorToResponse(author), nil
+}
+
+func (c *AuthorUseCase) Delete(ctx context.Context, request *model.DeleteAuthorRequest) error {
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return model.NewValidationError(err)
+	}
+
+	return c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		author := new(entity.Author)
+		if err := c.AuthorRepository.FindById(ctx, author, request.ID); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find author")
+			return fiber.ErrNotFound
+		}
+
+		// Books keep a foreign key to their author, so an author with books
+		// cannot be removed until those books are deleted or reassigned.
+		total, err := c.BookRepository.CountByAuthorId(ctx, author.ID)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to count books by author")
+			return fiber.ErrInternalServerError
+		}
+		if total > 0 {
+			pkg.Logger(ctx, c.Log).Warnf("Author still has books : %s", author.ID)
+			return fiber.ErrConflict
+		}
+
+		if err := c.AuthorRepository.Delete(ctx, author); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to delete author")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+}
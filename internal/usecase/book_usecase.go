@@ -8,67 +8,259 @@ import (
 	"github.com/manikandareas/go-clean-architecture/internal/entity"
 	"github.com/manikandareas/go-clean-architecture/internal/model"
 	"github.com/manikandareas/go-clean-architecture/internal/model/converter"
-	"github.com/manikandareas/go-clean-architecture/internal/repository"
+	"github.com/manikandareas/go-clean-architecture/pkg"
 	"github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
 type BookUseCase struct {
-	DB             *gorm.DB
-	Log            *logrus.Logger
-	Validate       *validator.Validate
-	BookRepository *repository.BookRepository
+	TxManager        TxManager
+	Log              *logrus.Logger
+	Validate         *validator.Validate
+	BookRepository   BookRepository
+	AuthorRepository AuthorRepository
+	CursorService    *pkg.CursorService
 }
 
-func NewBookUseCase(db *gorm.DB, log *logrus.Logger, validate *validator.Validate, bookRepository *repository.BookRepository) *BookUseCase {
+func NewBookUseCase(txManager TxManager, log *logrus.Logger, validate *validator.Validate, bookRepository BookRepository, authorRepository AuthorRepository, cursorService *pkg.CursorService) *BookUseCase {
 	return &BookUseCase{
-		DB:             db,
-		Log:            log,
-		Validate:       validate,
-		BookRepository: bookRepository,
+		TxManager:        txManager,
+		Log:              log,
+		Validate:         validate,
+		BookRepository:   bookRepository,
+		AuthorRepository: authorRepository,
+		CursorService:    cursorService,
 	}
 }
 
-func (c *BookUseCase) List(ctx context.Context) ([]model.BookResponse, error) {
-	tx := c.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
+func (c *BookUseCase) Search(ctx context.Context, request *model.SearchBookRequest) ([]model.BookResponse, int64, error) {
+	ctx, span := tracer.Start(ctx, "BookUseCase.Search")
+	defer span.End()
 
-	var err error
-	books := new([]entity.Book)
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, 0, model.NewValidationError(err)
+	}
+
+	var books []entity.Book
+	var total int64
+	err := c.TxManager.WithinReadOnlyTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if books, total, err = c.BookRepository.Search(ctx, request); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to search books")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return converter.BooksToResponse(&books), total, nil
+}
+
+// SearchByCursor pages through books with a keyset cursor instead of an
+// offset. Only creation time ordering is supported in this mode.
+func (c *BookUseCase) SearchByCursor(ctx context.Context, request *model.SearchBookRequest) ([]model.BookResponse, *model.PageMetadata, error) {
+	ctx, span := tracer.Start(ctx, "BookUseCase.SearchByCursor")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, nil, model.NewValidationError(err)
+	}
+	if request.Sort != "" && request.Sort != "created_at" && request.Sort != "-created_at" {
+		pkg.Logger(ctx, c.Log).Warnf("Unsupported cursor sort : %s", request.Sort)
+		return nil, nil, model.NewFieldError("sort", "oneof", "created_at -created_at", "sort must be one of [created_at -created_at] when paging by cursor")
+	}
 
-	if err = c.BookRepository.FindAll(tx, books); err != nil {
-		return nil, fiber.ErrNotFound
+	var cursor *model.Cursor
+	desc := request.Sort != "created_at"
+	if request.After != "" {
+		decoded, err := c.CursorService.Decode(request.After)
+		if err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Warn("failed to decode cursor")
+			return nil, nil, model.NewFieldError("after", "cursor", "", "after must be a cursor returned by a previous page")
+		}
+		cursor, desc = decoded, decoded.Desc
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		c.Log.WithError(err).Error("failed to commit transaction")
-		return nil, fiber.ErrInternalServerError
+	var books []entity.Book
+	var hasMore bool
+	err := c.TxManager.WithinReadOnlyTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if books, hasMore, err = c.BookRepository.SearchByCursor(ctx, request, cursor, desc); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to search books")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return converter.BooksToResponse(books), nil
+	paging := &model.PageMetadata{Size: request.Size, CursorPage: new(model.CursorPage)}
+	if len(books) > 0 {
+		backward := cursor != nil && cursor.Backward
+		first, last := books[0], books[len(books)-1]
+		// A backward page always has newer rows after it, a forward page has
+		// older rows before it whenever it started from a cursor.
+		if hasMore || backward {
+			if paging.NextCursor, err = c.CursorService.Encode(&model.Cursor{CreatedAt: last.CreatedAt, ID: last.ID, Desc: desc}); err != nil {
+				pkg.Logger(ctx, c.Log).WithError(err).Error("failed to encode cursor")
+				return nil, nil, fiber.ErrInternalServerError
+			}
+		}
+		if (hasMore && backward) || (cursor != nil && !backward) {
+			if paging.PrevCursor, err = c.CursorService.Encode(&model.Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Desc: desc, Backward: true}); err != nil {
+				pkg.Logger(ctx, c.Log).WithError(err).Error("failed to encode cursor")
+				return nil, nil, fiber.ErrInternalServerError
+			}
+		}
+	}
+
+	return converter.BooksToResponse(&books), paging, nil
 }
 
 func (c *BookUseCase) Create(ctx context.Context, request *model.BookRequest) (*model.BookResponse, error) {
-	tx := c.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
+	ctx, span := tracer.Start(ctx, "BookUseCase.Create")
+	defer span.End()
 
 	if err := c.Validate.Struct(request); err != nil {
-		c.Log.WithError(err).Error("failed to validate request body")
-		return nil, fiber.ErrBadRequest
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, model.NewValidationError(err)
 	}
 
 	book := &entity.Book{
 		ID:       uuid.NewString(),
 		Title:    request.Title,
 		AuthorId: request.AuthorId,
+		OwnerId:  request.UserId,
 	}
-	if err := c.BookRepository.Create(tx, book); err != nil {
-		c.Log.WithError(err).Error("failed to create book")
-		return nil, fiber.ErrInternalServerError
+	err := c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.ensureAuthorExists(ctx, request.AuthorId); err != nil {
+			return err
+		}
+		if err := c.BookRepository.Create(ctx, book); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to create book")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if err := tx.Commit().Error; err != nil {
-		c.Log.WithError(err).Error("failed to commit transaction")
-		return nil, fiber.ErrInternalServerError
+	return converter.BookToResponse(book), nil
+}
+
+func (c *BookUseCase) Get(ctx context.Context, request *model.GetBookRequest) (*model.BookResponse, error) {
+	ctx, span := tracer.Start(ctx, "BookUseCase.Get")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, model.NewValidationError(err)
+	}
+
+	// the author is preloaded by a second query that must see the same book
+	book := new(entity.Book)
+	err := c.TxManager.WithinReadOnlyTransaction(ctx, func(ctx context.Context) error {
+		if err := c.BookRepository.FindByIdIncluding(ctx, book, request.ID, request.IncludeAuthor); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find book")
+			return fiber.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return converter.BookToResponse(book), nil
 }
+
+func (c *BookUseCase) Update(ctx context.Context, request *model.UpdateBookRequest) (*model.BookResponse, error) {
+	ctx, span := tracer.Start(ctx, "BookUseCase.Update")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, model.NewValidationError(err)
+	}
+
+	book := new(entity.Book)
+	err := c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.BookRepository.FindById(ctx, book, request.ID); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find book")
+			return fiber.ErrNotFound
+		}
+		if !canModifyBook(book, request.UserId, request.IsAdmin) {
+			pkg.Logger(ctx, c.Log).Warnf("User %s is not allowed to update book %s", request.UserId, book.ID)
+			return fiber.ErrForbidden
+		}
+
+		if request.Title != "" {
+			book.Title = request.Title
+		}
+		if request.AuthorId != "" && request.AuthorId != book.AuthorId {
+			if err := c.ensureAuthorExists(ctx, request.AuthorId); err != nil {
+				return err
+			}
+			book.AuthorId = request.AuthorId
+		}
+
+		if err := c.BookRepository.Update(ctx, book); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to update book")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return converter.BookToResponse(book), nil
+}
+
+func (c *BookUseCase) Delete(ctx context.Context, request *model.DeleteBookRequest) error {
+	ctx, span := tracer.Start(ctx, "BookUseCase.Delete")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return model.NewValidationError(err)
+	}
+
+	return c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		book := new(entity.Book)
+		if err := c.BookRepository.FindById(ctx, book, request.ID); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find book")
+			return fiber.ErrNotFound
+		}
+		if !canModifyBook(book, request.UserId, request.IsAdmin) {
+			pkg.Logger(ctx, c.Log).Warnf("User %s is not allowed to delete book %s", request.UserId, book.ID)
+			return fiber.ErrForbidden
+		}
+
+		if err := c.BookRepository.Delete(ctx, book); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to delete book")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+}
+
+// ensureAuthorExists rejects books referencing an author that is not stored.
+func (c *BookUseCase) ensureAuthorExists(ctx context.Context, authorId string) error {
+	total, err := c.AuthorRepository.CountById(ctx, authorId)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to count author")
+		return fiber.ErrInternalServerError
+	}
+	if total == 0 {
+		pkg.Logger(ctx, c.Log).Warnf("Author not found : %s", authorId)
+		return model.NewFieldError("author_id", "exists", "", "author_id must reference an existing author")
+	}
+	return nil
+}
+
+// canModifyBook only lets the user who created a book, or an admin, change it.
+func canModifyBook(book *entity.Book, userId string, isAdmin bool) bool {
+	return isAdmin || (book.OwnerId != "" && book.OwnerId == userId)
+}
@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"strings"
+	"testing"
+)
+
+func TestCreateBook(t *testing.T) {
+	useCases := newUseCases(t)
+	author, err := useCases.Author.Create(context.Background(), &model.AuthorRequest{Name: "Pramoedya Ananta Toer"})
+	if err != nil {
+		t.Fatalf("creating the author failed: %v", err)
+	}
+
+	book, err := useCases.Book.Create(context.Background(), &model.BookRequest{
+		UserId:   "user-1",
+		Title:    "Bumi Manusia",
+		AuthorId: author.ID,
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if book.ID == "" || book.Title != "Bumi Manusia" || book.AuthorId != author.ID || book.OwnerId != "user-1" {
+		t.Fatalf("unexpected book %+v", book)
+	}
+
+	found, err := useCases.Book.Get(context.Background(), &model.GetBookRequest{ID: book.ID, IncludeAuthor: true})
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if found.Author == nil || found.Author.Name != author.Name {
+		t.Fatalf("Get did not include the author: %+v", found)
+	}
+
+	books, total, err := useCases.Book.Search(context.Background(), &model.SearchBookRequest{Title: "bumi", Page: 1, Size: 10})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if total != 1 || len(books) != 1 || books[0].ID != book.ID {
+		t.Fatalf("Search returned %d of %d books, want the created book", len(books), total)
+	}
+}
+
+func TestCreateBookRequiresExistingAuthor(t *testing.T) {
+	useCases := newUseCases(t)
+
+	_, err := useCases.Book.Create(context.Background(), &model.BookRequest{
+		UserId:   "user-1",
+		Title:    "Orphan",
+		AuthorId: "missing-author",
+	})
+	var modelError *model.Error
+	if !errors.As(err, &modelError) || len(modelError.Fields) != 1 || modelError.Fields[0].Field != "author_id" {
+		t.Fatalf("creating a book of a missing author returned %v, want an author_id field error", err)
+	}
+
+	_, total, err := useCases.Book.Search(context.Background(), &model.SearchBookRequest{Page: 1, Size: 10})
+	if err != nil || total != 0 {
+		t.Fatalf("rejected book was stored: total %d, err %v", total, err)
+	}
+}
+
+func TestSearchBooksByCursor(t *testing.T) {
+	useCases := newUseCases(t)
+	author, err := useCases.Author.Create(context.Background(), &model.AuthorRequest{Name: "Pramoedya Ananta Toer"})
+	if err != nil {
+		t.Fatalf("creating the author failed: %v", err)
+	}
+	for _, title := range []string{"Bumi Manusia", "Anak Semua Bangsa", "Jejak Langkah"} {
+		if _, err := useCases.Book.Create(context.Background(), &model.BookRequest{UserId: "user-1", Title: title, AuthorId: author.ID}); err != nil {
+			t.Fatalf("creating %s failed: %v", title, err)
+		}
+	}
+
+	first, paging, err := useCases.Book.SearchByCursor(context.Background(), &model.SearchBookRequest{Page: 1, Size: 2})
+	if err != nil {
+		t.Fatalf("SearchByCursor failed: %v", err)
+	}
+	if len(first) != 2 || paging.NextCursor == "" || paging.PrevCursor != "" {
+		t.Fatalf("first page holds %d books with cursors %+v, want 2 and only a next cursor", len(first), paging.CursorPage)
+	}
+
+	last, paging, err := useCases.Book.SearchByCursor(context.Background(), &model.SearchBookRequest{Page: 1, Size: 2, After: paging.NextCursor})
+	if err != nil {
+		t.Fatalf("SearchByCursor with the next cursor failed: %v", err)
+	}
+	if len(last) != 1 || paging.NextCursor != "" || paging.PrevCursor == "" {
+		t.Fatalf("last page holds %d books with cursors %+v, want 1 and only a prev cursor", len(last), paging.CursorPage)
+	}
+
+	payload, signature, _ := strings.Cut(paging.PrevCursor, ".")
+	tampered, _ := json.Marshal(model.Cursor{ID: last[0].ID, Backward: true})
+	foreign, err := pkg.NewCursorService(&model.AppConfig{Pagination: model.PaginationConfig{CursorSecret: "a-secret-of-another-deployment!!"}}).Encode(&model.Cursor{ID: last[0].ID})
+	if err != nil {
+		t.Fatalf("encoding a foreign cursor failed: %v", err)
+	}
+	for name, cursor := range map[string]string{
+		"tampered payload":  base64.RawURLEncoding.EncodeToString(tampered) + "." + signature,
+		"missing signature": payload,
+		"other secret":      foreign,
+	} {
+		_, _, err := useCases.Book.SearchByCursor(context.Background(), &model.SearchBookRequest{Page: 1, Size: 2, After: cursor})
+		var modelError *model.Error
+		if !errors.As(err, &modelError) || len(modelError.Fields) != 1 || modelError.Fields[0].Field != "after" {
+			t.Errorf("cursor with %s returned %v, want an after field error", name, err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// HealthChecker is a dependency the application needs in order to serve
+// traffic. Check must honour the context deadline.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type HealthUseCase struct {
+	Log      *logrus.Logger
+	Timeout  time.Duration
+	Checkers []HealthChecker
+}
+
+func NewHealthUseCase(log *logrus.Logger, timeout time.Duration, checkers ...HealthChecker) *HealthUseCase {
+	return &HealthUseCase{
+		Log:      log,
+		Timeout:  timeout,
+		Checkers: checkers,
+	}
+}
+
+// Register adds a checker to the readiness report. It must be called before
+// the server starts serving.
+func (c *HealthUseCase) Register(checker HealthChecker) {
+	c.Checkers = append(c.Checkers, checker)
+}
+
+// Live only reports that the process is able to answer.
+func (c *HealthUseCase) Live() *model.HealthResponse {
+	return &model.HealthResponse{Status: model.HealthStatusUp}
+}
+
+// Ready runs every checker concurrently, each bounded by Timeout, and is up
+// only when all of them are.
+func (c *HealthUseCase) Ready(ctx context.Context) *model.HealthResponse {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	components := make([]model.ComponentHealth, len(c.Checkers))
+	var wg sync.WaitGroup
+	for i, checker := range c.Checkers {
+		wg.Add(1)
+		go func(i int, checker HealthChecker) {
+			defer wg.Done()
+			start := time.Now()
+			err := checker.Check(ctx)
+			components[i] = model.ComponentHealth{
+				Status:    model.HealthStatusUp,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			// the probe is public, so the cause only goes to the log
+			if err != nil {
+				pkg.Logger(ctx, c.Log).WithError(err).Warnf("health check %s failed", checker.Name())
+				components[i].Status = model.HealthStatusDown
+			}
+		}(i, checker)
+	}
+	wg.Wait()
+
+	response := &model.HealthResponse{
+		Status:     model.HealthStatusUp,
+		Components: make(map[string]model.ComponentHealth, len(components)),
+	}
+	for i, component := range components {
+		response.Components[c.Checkers[i].Name()] = component
+		if component.Status != model.HealthStatusUp {
+			response.Status = model.HealthStatusDown
+		}
+	}
+	return response
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"time"
+)
+
+// The use cases depend on these interfaces rather than on the GORM
+// repositories, so tests can swap in the implementations of
+// repository/memory. Every method runs in the transaction TxManager stored in
+// its context, or on its own outside of one.
+
+// TxManager runs fn in a transaction carried by the context fn receives.
+// Within a transaction it opens a nested one that only undoes its own writes
+// when fn fails.
+type TxManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinReadOnlyTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Repository[T any] interface {
+	Create(ctx context.Context, entity *T) error
+	Update(ctx context.Context, entity *T) error
+	Delete(ctx context.Context, entity *T) error
+	CountById(ctx context.Context, id any) (int64, error)
+	FindById(ctx context.Context, entity *T, id any) error
+}
+
+type UserRepository interface {
+	Repository[entity.User]
+	FindByIdWithRoles(ctx context.Context, user *entity.User, id string) error
+	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	FindByEmails(ctx context.Context, users *[]entity.User, emails []string) error
+	FindWithoutRoles(ctx context.Context, users *[]entity.User) error
+	ReplaceRoles(ctx context.Context, user *entity.User, roles []entity.Role) error
+}
+
+type RoleRepository interface {
+	Repository[entity.Role]
+	FindAll(ctx context.Context, roles *[]entity.Role) error
+	FindByNames(ctx context.Context, roles *[]entity.Role, names []string) error
+	FindByUserId(ctx context.Context, roles *[]entity.Role, userId string) error
+	FirstOrCreateByName(ctx context.Context, role *entity.Role, name string) error
+	ReplacePermissions(ctx context.Context, role *entity.Role, permissions []entity.Permission) error
+}
+
+type PermissionRepository interface {
+	Repository[entity.Permission]
+	FirstOrCreateByName(ctx context.Context, permission *entity.Permission, name string) error
+}
+
+type RevokedTokenRepository interface {
+	Repository[entity.RevokedToken]
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type RefreshTokenRepository interface {
+	Repository[entity.RefreshToken]
+	FindByTokenHash(ctx context.Context, refreshToken *entity.RefreshToken, tokenHash string) error
+	MarkUsed(ctx context.Context, id string, now time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyId string, now time.Time) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type BookRepository interface {
+	Repository[entity.Book]
+	FindByIdIncluding(ctx context.Context, book *entity.Book, id string, includeAuthor bool) error
+	Search(ctx context.Context, request *model.SearchBookRequest) ([]entity.Book, int64, error)
+	SearchByCursor(ctx context.Context, request *model.SearchBookRequest, cursor *model.Cursor, desc bool) ([]entity.Book, bool, error)
+	CountByAuthorId(ctx context.Context, authorId string) (int64, error)
+}
+
+type AuthorRepository interface {
+	Repository[entity.Author]
+	Search(ctx context.Context, request *model.SearchAuthorRequest) ([]entity.Author, int64, error)
+}
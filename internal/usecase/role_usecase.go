@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/manikandareas/go-clean-architecture/internal/entity"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/model/converter"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultRoles is the authoritative role to permission mapping, Seed
+// synchronises the database with it on every start.
+var defaultRoles = map[string][]string{
+	model.RoleUser: {
+		model.PermissionBooksRead,
+		model.PermissionAuthorsRead,
+	},
+	model.RoleEditor: {
+		model.PermissionBooksRead,
+		model.PermissionBooksWrite,
+		model.PermissionAuthorsRead,
+		model.PermissionAuthorsWrite,
+	},
+	model.RoleAdmin: {
+		model.PermissionBooksRead,
+		model.PermissionBooksWrite,
+		model.PermissionAuthorsRead,
+		model.PermissionAuthorsWrite,
+		model.PermissionRolesAssign,
+	},
+}
+
+type RoleUseCase struct {
+	TxManager            TxManager
+	Log                  *logrus.Logger
+	Validate             *validator.Validate
+	RoleRepository       RoleRepository
+	PermissionRepository PermissionRepository
+	UserRepository       UserRepository
+}
+
+func NewRoleUseCase(txManager TxManager, log *logrus.Logger, validate *validator.Validate, roleRepository RoleRepository, permissionRepository PermissionRepository, userRepository UserRepository) *RoleUseCase {
+	return &RoleUseCase{
+		TxManager:            txManager,
+		Log:                  log,
+		Validate:             validate,
+		RoleRepository:       roleRepository,
+		PermissionRepository: permissionRepository,
+		UserRepository:       userRepository,
+	}
+}
+
+// Seed creates the default roles and permissions, grants the user role to
+// accounts without any role and promotes the configured admin emails. The
+// admin list only grants: removing an email does not demote the account,
+// as admins appointed through Assign look the same, demote it through Assign.
+func (c *RoleUseCase) Seed(ctx context.Context, adminEmails []string) error {
+	return c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		roles := make(map[string]*entity.Role, len(defaultRoles))
+		for name, permissionNames := range defaultRoles {
+			role := new(entity.Role)
+			if err := c.RoleRepository.FirstOrCreateByName(ctx, role, name); err != nil {
+				pkg.Logger(ctx, c.Log).WithError(err).Error("failed to seed role")
+				return err
+			}
+
+			permissions := make([]entity.Permission, len(permissionNames))
+			for i, permissionName := range permissionNames {
+				if err := c.PermissionRepository.FirstOrCreateByName(ctx, &permissions[i], permissionName); err != nil {
+					pkg.Logger(ctx, c.Log).WithError(err).Error("failed to seed permission")
+					return err
+				}
+			}
+			if err := c.RoleRepository.ReplacePermissions(ctx, role, permissions); err != nil {
+				pkg.Logger(ctx, c.Log).WithError(err).Error("failed to seed role permissions")
+				return err
+			}
+			roles[name] = role
+		}
+
+		var users []entity.User
+		if err := c.UserRepository.FindWithoutRoles(ctx, &users); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find users without role")
+			return err
+		}
+		for i := range users {
+			if err := c.UserRepository.ReplaceRoles(ctx, &users[i], []entity.Role{*roles[model.RoleUser]}); err != nil {
+				pkg.Logger(ctx, c.Log).WithError(err).Error("failed to grant default role")
+				return err
+			}
+		}
+
+		if len(adminEmails) > 0 {
+			var admins []entity.User
+			if err := c.UserRepository.FindByEmails(ctx, &admins, adminEmails); err != nil {
+				pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find admin users")
+				return err
+			}
+			for i := range admins {
+				if err := c.UserRepository.ReplaceRoles(ctx, &admins[i], []entity.Role{*roles[model.RoleAdmin]}); err != nil {
+					pkg.Logger(ctx, c.Log).WithError(err).Error("failed to grant admin role")
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (c *RoleUseCase) List(ctx context.Context) ([]model.RoleResponse, error) {
+	// the permissions are preloaded by a second query that must see the same roles
+	var roles []entity.Role
+	err := c.TxManager.WithinReadOnlyTransaction(ctx, func(ctx context.Context) error {
+		if err := c.RoleRepository.FindAll(ctx, &roles); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find roles")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return converter.RolesToResponse(&roles), nil
+}
+
+// Assign replaces the roles of a user. The new permissions apply to tokens
+// issued from the next login or refresh onwards.
+func (c *RoleUseCase) Assign(ctx context.Context, request *model.AssignRoleRequest) (*model.UserResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).WithError(err).Error("failed to validate request body")
+		return nil, model.NewValidationError(err)
+	}
+
+	user := new(entity.User)
+	var roles []entity.Role
+	err := c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.UserRepository.FindById(ctx, user, request.UserId); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find user")
+			return fiber.ErrNotFound
+		}
+
+		if err := c.RoleRepository.FindByNames(ctx, &roles, request.Roles); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to find roles")
+			return fiber.ErrInternalServerError
+		}
+		if len(roles) != len(request.Roles) {
+			pkg.Logger(ctx, c.Log).Warnf("Unknown role in : %v", request.Roles)
+			return model.NewFieldError("roles", "exists", "", "roles must only contain existing roles")
+		}
+
+		if err := c.UserRepository.ReplaceRoles(ctx, user, roles); err != nil {
+			pkg.Logger(ctx, c.Log).WithError(err).Error("failed to assign roles")
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	user.Roles = roles
+	return converter.UserToResponse(user), nil
+}
@@ -0,0 +1,36 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"slices"
+	"testing"
+)
+
+func TestSeedAdmins(t *testing.T) {
+	useCases := newUseCases(t)
+	user := register(t, useCases, "admin@example.com")
+	roles := func() []string {
+		t.Helper()
+		response, err := useCases.User.Current(context.Background(), &model.GetUserRequest{ID: user.ID})
+		if err != nil {
+			t.Fatalf("Current failed: %v", err)
+		}
+		return response.Roles
+	}
+
+	if err := useCases.Role.Seed(context.Background(), []string{"admin@example.com"}); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	if got := roles(); !slices.Equal(got, []string{model.RoleAdmin}) {
+		t.Fatalf("roles after seeding the admin = %v, want %v", got, []string{model.RoleAdmin})
+	}
+
+	// the list only grants, dropping the email keeps the role
+	if err := useCases.Role.Seed(context.Background(), nil); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+	if got := roles(); !slices.Equal(got, []string{model.RoleAdmin}) {
+		t.Fatalf("roles after seeding without the admin = %v, want %v", got, []string{model.RoleAdmin})
+	}
+}
@@ -0,0 +1,7 @@
+package usecase
+
+import "go.opentelemetry.io/otel"
+
+// tracer opens one span per use case call; GORM spans nest beneath it as
+// long as the transaction is started from the returned context.
+var tracer = otel.Tracer("github.com/manikandareas/go-clean-architecture/internal/usecase")
@@ -0,0 +1,65 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/internal/repository/memory"
+	"github.com/manikandareas/go-clean-architecture/internal/usecase"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/sirupsen/logrus"
+	"io"
+	"testing"
+	"time"
+)
+
+// useCases wires the use cases over a fresh in-memory store.
+type useCases struct {
+	User       *usecase.UserUseCase
+	Book       *usecase.BookUseCase
+	Author     *usecase.AuthorUseCase
+	Role       *usecase.RoleUseCase
+	JwtService *pkg.JwtService
+	Metrics    *pkg.Metrics
+}
+
+func newUseCases(t *testing.T) *useCases {
+	t.Helper()
+
+	store := memory.NewStore()
+	txManager := memory.NewTxManager(store)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	validate := validator.New()
+
+	appConfig := &model.AppConfig{
+		Jwt: model.JwtConfig{
+			AccessToken:     "access-token-secret-for-unit-tests",
+			RefreshToken:    "refresh-token-secret-for-unit-tests",
+			AccessTokenTtl:  time.Hour,
+			RefreshTokenTtl: 24 * time.Hour,
+		},
+		Pagination: model.PaginationConfig{CursorSecret: "cursor-secret-for-the-unit-tests"},
+	}
+	jwtService := pkg.NewJwtService(pkg.NewSettings(appConfig))
+	metrics := pkg.NewMetrics()
+
+	userRepository := memory.NewUserRepository(store)
+	roleRepository := memory.NewRoleRepository(store)
+	bookRepository := memory.NewBookRepository(store)
+	authorRepository := memory.NewAuthorRepository(store)
+
+	roleUseCase := usecase.NewRoleUseCase(txManager, log, validate, roleRepository, memory.NewPermissionRepository(store), userRepository)
+	if err := roleUseCase.Seed(context.Background(), nil); err != nil {
+		t.Fatalf("failed to seed roles: %v", err)
+	}
+
+	return &useCases{
+		User:       usecase.NewUserUseCase(txManager, log, validate, userRepository, memory.NewRevokedTokenRepository(store), memory.NewRefreshTokenRepository(store), roleRepository, jwtService, metrics),
+		Book:       usecase.NewBookUseCase(txManager, log, validate, bookRepository, authorRepository, pkg.NewCursorService(appConfig)),
+		Author:     usecase.NewAuthorUseCase(txManager, log, validate, authorRepository, bookRepository),
+		Role:       roleUseCase,
+		JwtService: jwtService,
+		Metrics:    metrics,
+	}
+}
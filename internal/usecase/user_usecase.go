@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
@@ -9,24 +12,28 @@ import (
 	"github.com/manikandareas/go-clean-architecture/internal/entity"
 	"github.com/manikandareas/go-clean-architecture/internal/model"
 	"github.com/manikandareas/go-clean-architecture/internal/model/converter"
-	"github.com/manikandareas/go-clean-architecture/internal/repository"
 	"github.com/manikandareas/go-clean-architecture/pkg"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"slices"
 	"time"
 )
 
 type UserUseCase struct {
-	DB             *gorm.DB
-	Log            *logrus.Logger
-	Validate       *validator.Validate
-	UserRepository *repository.UserRepository
-	JwtService     *pkg.JwtService
+	TxManager              TxManager
+	Log                    *logrus.Logger
+	Validate               *validator.Validate
+	UserRepository         UserRepository
+	RevokedTokenRepository RevokedTokenRepository
+	RefreshTokenRepository RefreshTokenRepository
+	RoleRepository         RoleRepository
+	JwtService             *pkg.JwtService
+	Metrics                *pkg.Metrics
 }
 
-func NewUserUseCase(DB *gorm.DB, log *logrus.Logger, validate *validator.Validate, userRepository *repository.UserRepository, jwtService *pkg.JwtService) *UserUseCase {
-	return &UserUseCase{DB: DB, Log: log, Validate: validate, UserRepository: userRepository, JwtService: jwtService}
+func NewUserUseCase(txManager TxManager, log *logrus.Logger, validate *validator.Validate, userRepository UserRepository, revokedTokenRepository RevokedTokenRepository, refreshTokenRepository RefreshTokenRepository, roleRepository RoleRepository, jwtService *pkg.JwtService, metrics *pkg.Metrics) *UserUseCase {
+	return &UserUseCase{TxManager: txManager, Log: log, Validate: validate, UserRepository: userRepository, RevokedTokenRepository: revokedTokenRepository, RefreshTokenRepository: refreshTokenRepository, RoleRepository: roleRepository, JwtService: jwtService, Metrics: metrics}
 }
 
 // TODO: Refactor Verify to unused token from db
@@ -35,103 +42,162 @@ func NewUserUseCase(DB *gorm.DB, log *logrus.Logger, validate *validator.Validat
 //
 // ctx - The context for the request.
 // request - The authentication request to be verified.
-// Duties - count user by id from database to ensure request from a valid user
+// Duties - count user by id from database to ensure request from a valid user,
+// and reject tokens without a jti or whose jti has been revoked
 // error - An error, if any.
 
 func (c *UserUseCase) Verify(ctx context.Context, request *model.Auth) error {
-	tx := c.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
+	ctx, span := tracer.Start(ctx, "UserUseCase.Verify")
+	defer span.End()
 
 	if err := c.Validate.Struct(request); err != nil {
-		c.Log.Warnf("Invalid request body : %+v", err)
-		return fiber.ErrBadRequest
+		pkg.Logger(ctx, c.Log).Warnf("Invalid request body : %+v", err)
+		return model.NewValidationError(err)
 	}
 
-	count, err := c.UserRepository.CountById(tx, request.ID)
+	count, err := c.UserRepository.CountById(ctx, request.ID)
 	if err != nil {
-		c.Log.Warnf("Failed find by user id : %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("Failed find by user id : %+v", err)
 		return fiber.ErrNotFound
 	}
 
 	if !(count > 0) {
-		c.Log.Warnf("User not found : %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("User not found : %+v", err)
 		return fiber.ErrNotFound
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		c.Log.Warnf("Failed commit transaction : %+v", err)
+	if request.TokenID == "" {
+		pkg.Logger(ctx, c.Log).Warnf("Token has no jti : %s", request.ID)
+		return fiber.ErrUnauthorized
+	}
+	revoked, err := c.RevokedTokenRepository.CountById(ctx, request.TokenID)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed find revoked token : %+v", err)
 		return fiber.ErrInternalServerError
 	}
+	if revoked > 0 {
+		pkg.Logger(ctx, c.Log).Warnf("Token has been revoked : %s", request.TokenID)
+		return fiber.ErrUnauthorized
+	}
 	return nil
 }
 
-func (c *UserUseCase) RefreshToken(ctx context.Context, request *model.Auth) (*model.BackendTokens, error) {
-	tx := c.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
+// RefreshToken rotates a refresh token: the presented token is consumed and
+// a new pair is issued in the same token family. Presenting a token that was
+// already consumed or revoked revokes the whole family.
+func (c *UserUseCase) RefreshToken(ctx context.Context, request *model.RefreshTokenRequest) (*model.BackendTokens, error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.RefreshToken")
+	defer span.End()
+
+	result := pkg.MetricResultFailure
+	defer func() {
+		c.Metrics.TokenRefreshes.WithLabelValues(result).Inc()
+	}()
 
 	if err := c.Validate.Struct(request); err != nil {
-		c.Log.Warnf("Invalid request body : %+v", err)
-		return nil, fiber.ErrBadRequest
-	}
-	expireTime := jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 7))
-	claimsAccessToken := &model.JwtClaims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: expireTime,
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-		User: map[string]string{
-			"user_id": request.ID,
-			"email":   request.Email,
-			"name":    request.Name,
-		},
-	}
-	accessToken, err := c.JwtService.GenerateJwtToken(claimsAccessToken, pkg.ACCESS_TOKEN_KEY)
-	if err != nil {
-		c.Log.Warnf("Failed to generate jwt token : %+v", err)
-		return nil, fiber.ErrInternalServerError
+		pkg.Logger(ctx, c.Log).Warnf("Invalid request body : %+v", err)
+		return nil, model.NewValidationError(err)
 	}
-	claimsRefreshToken := &model.JwtClaims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 30)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-		User: map[string]string{
-			"user_id": request.ID,
-			"email":   request.Email,
-			"name":    request.Name,
-		},
-	}
-	refreshToken, err := c.JwtService.GenerateJwtToken(claimsRefreshToken, pkg.REFRESH_TOKEN_KEY)
+
+	now := time.Now()
+	refreshToken := new(entity.RefreshToken)
+	var backendTokens *model.BackendTokens
+	reused := false
+	err := c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.RefreshTokenRepository.FindByTokenHash(ctx, refreshToken, hashToken(request.Token)); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed find refresh token : %+v", err)
+			return fiber.ErrUnauthorized
+		}
+		if refreshToken.UserId != request.ID || refreshToken.ExpiresAt.Before(now) {
+			pkg.Logger(ctx, c.Log).Warnf("Invalid refresh token : %s", refreshToken.ID)
+			return fiber.ErrUnauthorized
+		}
+
+		// a token revoked by logout or along with its family is simply dead,
+		// only presenting a used one again points at a stolen copy
+		if refreshToken.RevokedAt != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Refresh token has been revoked : %s", refreshToken.ID)
+			return fiber.ErrUnauthorized
+		}
+
+		var consumed bool
+		var err error
+		if refreshToken.UsedAt == nil {
+			if consumed, err = c.RefreshTokenRepository.MarkUsed(ctx, refreshToken.ID, now); err != nil {
+				pkg.Logger(ctx, c.Log).Warnf("Failed consume refresh token : %+v", err)
+				return fiber.ErrInternalServerError
+			}
+			if !consumed {
+				// a concurrent request got to the token first, which is reuse
+				// only if it used the token rather than revoked it
+				if err := c.RefreshTokenRepository.FindById(ctx, refreshToken, refreshToken.ID); err != nil {
+					pkg.Logger(ctx, c.Log).Warnf("Failed find refresh token : %+v", err)
+					return fiber.ErrInternalServerError
+				}
+				if refreshToken.UsedAt == nil {
+					pkg.Logger(ctx, c.Log).Warnf("Refresh token has been revoked : %s", refreshToken.ID)
+					return fiber.ErrUnauthorized
+				}
+			}
+		}
+		if !consumed {
+			// Only a stolen copy can present a token twice, so every token
+			// rotated from the same login is revoked. The revocation must be
+			// committed, so the request is only rejected afterwards.
+			if err := c.RefreshTokenRepository.RevokeFamily(ctx, refreshToken.FamilyId, now); err != nil {
+				pkg.Logger(ctx, c.Log).Warnf("Failed revoke refresh token family : %+v", err)
+				return fiber.ErrInternalServerError
+			}
+			reused = true
+			return nil
+		}
+
+		user := new(entity.User)
+		if err := c.UserRepository.FindById(ctx, user, request.ID); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed find user by id : %+v", err)
+			return fiber.ErrUnauthorized
+		}
+
+		if backendTokens, err = c.issueTokens(ctx, user, refreshToken.FamilyId); err != nil {
+			return err
+		}
+		user.Token = backendTokens.AccessToken
+		if err := c.UserRepository.Update(ctx, user); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed save user : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
 	if err != nil {
-		c.Log.Warnf("Failed to generate jwt token : %+v", err)
-		return nil, fiber.ErrInternalServerError
+		return nil, err
 	}
-	backendToken := &model.BackendTokens{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		ExpiresIn:    expireTime.Unix(),
+
+	if reused {
+		pkg.Logger(ctx, c.Log).WithFields(logrus.Fields{
+			"event":     "refresh_token_reuse",
+			"user_id":   refreshToken.UserId,
+			"family_id": refreshToken.FamilyId,
+			"token_id":  refreshToken.ID,
+		}).Warn("Refresh token reuse detected, token family revoked")
+		result = pkg.MetricResultReuse
+		return nil, fiber.ErrUnauthorized
 	}
-	return backendToken, nil
+	result = pkg.MetricResultSuccess
+	return backendTokens, nil
 }
 
 func (c *UserUseCase) Register(ctx context.Context, request *model.RegisterUserRequest) (*model.UserResponse, error) {
-	tx := c.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
+	ctx, span := tracer.Start(ctx, "UserUseCase.Register")
+	defer span.End()
 
 	if err := c.Validate.Struct(request); err != nil {
-		c.Log.Warnf("Invalid request body : %+v", err)
-		return nil, fiber.ErrBadRequest
-	}
-
-	registeredUser, err := c.UserRepository.FindByEmail(tx, request.Email)
-	if registeredUser.Email != "" {
-		c.Log.Warnf("User already exists : %+v", err)
-		return nil, fiber.ErrConflict
+		pkg.Logger(ctx, c.Log).Warnf("Invalid request body : %+v", err)
+		return nil, model.NewValidationError(err)
 	}
 
 	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
-		c.Log.Warnf("Failed to hash password : %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("Failed to hash password : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
@@ -141,67 +207,121 @@ func (c *UserUseCase) Register(ctx context.Context, request *model.RegisterUserR
 		Email:    request.Email,
 		Name:     request.Name,
 	}
+	err = c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := c.UserRepository.FindByEmail(ctx, request.Email)
+		if err == nil {
+			pkg.Logger(ctx, c.Log).Warnf("User already exists : %s", request.Email)
+			return fiber.ErrConflict
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			pkg.Logger(ctx, c.Log).Warnf("Failed find by user email : %+v", err)
+			return fiber.ErrInternalServerError
+		}
 
-	if err := c.UserRepository.Create(tx, user); err != nil {
-		c.Log.Warnf("Failed to create user : %+v", err)
-		return nil, fiber.ErrInternalServerError
-	}
+		// new accounts start with the least privileged role
+		if err := c.RoleRepository.FindByNames(ctx, &user.Roles, []string{model.RoleUser}); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed find default role : %+v", err)
+			return fiber.ErrInternalServerError
+		}
 
-	if err := tx.Commit().Error; err != nil {
-		c.Log.Warnf("Failed commit transaction : %+v", err)
-		return nil, fiber.ErrInternalServerError
+		// the unique email index settles a registration racing this one
+		if err := c.UserRepository.Create(ctx, user); errors.Is(err, gorm.ErrDuplicatedKey) {
+			pkg.Logger(ctx, c.Log).Warnf("User already exists : %s", request.Email)
+			return fiber.ErrConflict
+		} else if err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed to create user : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return converter.UserToResponse(user), nil
 }
 
 func (c *UserUseCase) Login(ctx context.Context, request *model.LoginUserRequest) (*model.LoginUserResponse, error) {
-	tx := c.DB.WithContext(ctx).Begin()
-	defer tx.Rollback()
+	ctx, span := tracer.Start(ctx, "UserUseCase.Login")
+	defer span.End()
+
+	result := pkg.MetricResultFailure
+	defer func() {
+		c.Metrics.Logins.WithLabelValues(result).Inc()
+	}()
 
 	if err := c.Validate.Struct(request); err != nil {
-		c.Log.Warnf("Invalid request body : %+v", err)
-		return nil, fiber.ErrBadRequest
+		pkg.Logger(ctx, c.Log).Warnf("Invalid request body : %+v", err)
+		return nil, model.NewValidationError(err)
 	}
 
-	user, err := c.UserRepository.FindByEmail(tx, request.Email)
+	var user *entity.User
+	var backendTokens *model.BackendTokens
+	err := c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = c.UserRepository.FindByEmail(ctx, request.Email); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed find by user email : %+v", err)
+			return fiber.ErrUnauthorized
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed to compare user password with bcrypt hash : %+v", err)
+			return fiber.ErrUnauthorized
+		}
+		// every login starts a new refresh token family
+		if backendTokens, err = c.issueTokens(ctx, user, uuid.NewString()); err != nil {
+			return err
+		}
+		user.Token = backendTokens.AccessToken
+		if err := c.UserRepository.Update(ctx, user); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed save user : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
 	if err != nil {
-		c.Log.Warnf("Failed find by user email : %+v", err)
-		return nil, fiber.ErrUnauthorized
+		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
-		c.Log.Warnf("Failed to compare user password with bcrypt hash : %+v", err)
-		return nil, fiber.ErrUnauthorized
+	result = pkg.MetricResultSuccess
+	return converter.UserToLoginResponse(user, backendTokens), nil
+}
+
+// issueTokens mints an access token and a refresh token for the user and
+// persists the hashed refresh token under the given token family.
+func (c *UserUseCase) issueTokens(ctx context.Context, user *entity.User, familyId string) (*model.BackendTokens, error) {
+	var roles []entity.Role
+	if err := c.RoleRepository.FindByUserId(ctx, &roles, user.ID); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed find user roles : %+v", err)
+		return nil, fiber.ErrInternalServerError
 	}
-	expireTime := jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 7))
+	roleNames, permissionNames := rolesToClaims(roles)
+
+	now := time.Now()
+	expireTime := jwt.NewNumericDate(now.Add(c.JwtService.TokenTtl(pkg.ACCESS_TOKEN_KEY)))
 	claimsAccessToken := &model.JwtClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: expireTime,
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 		User: map[string]string{
 			"user_id": user.ID,
 			"email":   user.Email,
 			"name":    user.Name,
 		},
+		Roles:       roleNames,
+		Permissions: permissionNames,
 	}
-
 	accessToken, err := c.JwtService.GenerateJwtToken(claimsAccessToken, pkg.ACCESS_TOKEN_KEY)
 	if err != nil {
-		c.Log.Warnf("Failed to generate jwt token : %+v", err)
-		return nil, fiber.ErrInternalServerError
-	}
-	user.Token = accessToken
-	if err := c.UserRepository.Update(tx, user); err != nil {
-		c.Log.Warnf("Failed save user : %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("Failed to generate jwt token : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
 	claimsRefreshToken := &model.JwtClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 30)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(c.JwtService.TokenTtl(pkg.REFRESH_TOKEN_KEY))),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 		User: map[string]string{
 			"user_id": user.ID,
@@ -209,17 +329,224 @@ func (c *UserUseCase) Login(ctx context.Context, request *model.LoginUserRequest
 			"name":    user.Name,
 		},
 	}
-
 	refreshToken, err := c.JwtService.GenerateJwtToken(claimsRefreshToken, pkg.REFRESH_TOKEN_KEY)
 	if err != nil {
-		c.Log.Warnf("Failed to generate jwt token : %+v", err)
+		pkg.Logger(ctx, c.Log).Warnf("Failed to generate jwt token : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		c.Log.Warnf("Failed commit transaction : %+v", err)
+	record := &entity.RefreshToken{
+		ID:        claimsRefreshToken.ID,
+		FamilyId:  familyId,
+		UserId:    user.ID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: claimsRefreshToken.ExpiresAt.Time,
+	}
+	if err := c.RefreshTokenRepository.Create(ctx, record); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed to save refresh token : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return converter.UserToLoginResponse(user, expireTime, accessToken, refreshToken), nil
+	return &model.BackendTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    expireTime.Unix(),
+	}, nil
+}
+
+// rolesToClaims flattens roles into their names and the distinct, sorted
+// set of permissions they grant.
+func rolesToClaims(roles []entity.Role) ([]string, []string) {
+	roleNames := make([]string, 0, len(roles))
+	var permissionNames []string
+	for _, role := range roles {
+		roleNames = append(roleNames, role.Name)
+		for _, permission := range role.Permissions {
+			permissionNames = append(permissionNames, permission.Name)
+		}
+	}
+	slices.Sort(permissionNames)
+	return roleNames, slices.Compact(permissionNames)
+}
+
+// hashToken returns the hex encoded SHA-256 of a token, refresh tokens are
+// only ever stored in this form.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *UserUseCase) Current(ctx context.Context, request *model.GetUserRequest) (*model.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.Current")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Invalid request body : %+v", err)
+		return nil, model.NewValidationError(err)
+	}
+
+	// the roles are preloaded by a second query that must see the same user
+	user := new(entity.User)
+	err := c.TxManager.WithinReadOnlyTransaction(ctx, func(ctx context.Context) error {
+		if err := c.UserRepository.FindByIdWithRoles(ctx, user, request.ID); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed find user by id : %+v", err)
+			return fiber.ErrNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.UserToResponse(user), nil
+}
+
+func (c *UserUseCase) Update(ctx context.Context, request *model.UpdateUserRequest) (*model.UserResponse, error) {
+	ctx, span := tracer.Start(ctx, "UserUseCase.Update")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Invalid request body : %+v", err)
+		return nil, model.NewValidationError(err)
+	}
+
+	user := new(entity.User)
+	err := c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := c.UserRepository.FindById(ctx, user, request.ID); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed find user by id : %+v", err)
+			return fiber.ErrNotFound
+		}
+
+		if request.Name != "" {
+			user.Name = request.Name
+		}
+
+		if request.Email != "" && request.Email != user.Email {
+			_, err := c.UserRepository.FindByEmail(ctx, request.Email)
+			if err == nil {
+				pkg.Logger(ctx, c.Log).Warnf("Email already used : %s", request.Email)
+				return fiber.ErrConflict
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				pkg.Logger(ctx, c.Log).Warnf("Failed find by user email : %+v", err)
+				return fiber.ErrInternalServerError
+			}
+			user.Email = request.Email
+		}
+
+		if request.Password != "" {
+			// Changing the password requires proving knowledge of the current
+			// one. The caller is authenticated already, so a wrong one is a
+			// field error rather than a 401 that clients take for a lost session.
+			if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.CurrentPassword)); err != nil {
+				pkg.Logger(ctx, c.Log).Warnf("Failed to compare user password with bcrypt hash : %+v", err)
+				return model.NewFieldError("current_password", "password", "", "current_password must be the current password of the user")
+			}
+			password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+			if err != nil {
+				pkg.Logger(ctx, c.Log).Warnf("Failed to hash password : %+v", err)
+				return fiber.ErrInternalServerError
+			}
+			user.Password = string(password)
+		}
+
+		if err := c.UserRepository.Update(ctx, user); errors.Is(err, gorm.ErrDuplicatedKey) {
+			pkg.Logger(ctx, c.Log).Warnf("Email already used : %s", request.Email)
+			return fiber.ErrConflict
+		} else if err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed save user : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.UserToResponse(user), nil
+}
+
+func (c *UserUseCase) Logout(ctx context.Context, request *model.LogoutUserRequest) error {
+	ctx, span := tracer.Start(ctx, "UserUseCase.Logout")
+	defer span.End()
+
+	if err := c.Validate.Struct(request); err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Invalid request body : %+v", err)
+		return model.NewValidationError(err)
+	}
+
+	return c.TxManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user := new(entity.User)
+		if err := c.UserRepository.FindById(ctx, user, request.ID); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed find user by id : %+v", err)
+			return fiber.ErrNotFound
+		}
+
+		revokedTokens := []*entity.RevokedToken{
+			{ID: request.TokenID, UserId: user.ID, ExpiresAt: request.ExpiresAt},
+		}
+		if request.RefreshToken != "" {
+			refresh, err := c.JwtService.DecodeAuth(request.RefreshToken, pkg.REFRESH_TOKEN_KEY)
+			if err != nil || refresh.ID != user.ID || refresh.TokenID == "" {
+				pkg.Logger(ctx, c.Log).Warnf("Invalid refresh token : %+v", err)
+				return model.NewFieldError("refresh_token", "token", "", "refresh_token must be a valid refresh token of the current user")
+			}
+			revokedTokens = append(revokedTokens, &entity.RevokedToken{ID: refresh.TokenID, UserId: user.ID, ExpiresAt: refresh.ExpiresAt})
+
+			refreshToken := new(entity.RefreshToken)
+			if err := c.RefreshTokenRepository.FindByTokenHash(ctx, refreshToken, hashToken(request.RefreshToken)); err == nil {
+				if err := c.RefreshTokenRepository.RevokeFamily(ctx, refreshToken.FamilyId, time.Now()); err != nil {
+					pkg.Logger(ctx, c.Log).Warnf("Failed revoke refresh token family : %+v", err)
+					return fiber.ErrInternalServerError
+				}
+			}
+		}
+
+		for _, revokedToken := range revokedTokens {
+			count, err := c.RevokedTokenRepository.CountById(ctx, revokedToken.ID)
+			if err != nil {
+				pkg.Logger(ctx, c.Log).Warnf("Failed find revoked token : %+v", err)
+				return fiber.ErrInternalServerError
+			}
+			if count > 0 {
+				continue
+			}
+			if err := c.RevokedTokenRepository.Create(ctx, revokedToken); err != nil {
+				pkg.Logger(ctx, c.Log).Warnf("Failed revoke token : %+v", err)
+				return fiber.ErrInternalServerError
+			}
+		}
+
+		user.Token = ""
+		if err := c.UserRepository.Update(ctx, user); err != nil {
+			pkg.Logger(ctx, c.Log).Warnf("Failed save user : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+		return nil
+	})
+}
+
+// DeleteExpiredTokens drops the denylist entries and refresh token records
+// of tokens that have expired, which signature checks reject anyway. It is
+// run periodically rather than by the requests that revoke tokens.
+func (c *UserUseCase) DeleteExpiredTokens(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "UserUseCase.DeleteExpiredTokens")
+	defer span.End()
+
+	now := time.Now()
+	revoked, err := c.RevokedTokenRepository.DeleteExpired(ctx, now)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed delete expired revoked tokens : %+v", err)
+		return err
+	}
+	refresh, err := c.RefreshTokenRepository.DeleteExpired(ctx, now)
+	if err != nil {
+		pkg.Logger(ctx, c.Log).Warnf("Failed delete expired refresh tokens : %+v", err)
+		return err
+	}
+	pkg.Logger(ctx, c.Log).WithFields(logrus.Fields{
+		"revoked_tokens": revoked,
+		"refresh_tokens": refresh,
+	}).Debug("deleted expired tokens")
+	return nil
 }
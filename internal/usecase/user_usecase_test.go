@@ -0,0 +1,276 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"github.com/manikandareas/go-clean-architecture/pkg"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func register(t *testing.T, useCases *useCases, email string) *model.UserResponse {
+	t.Helper()
+	user, err := useCases.User.Register(context.Background(), &model.RegisterUserRequest{
+		Email:    email,
+		Password: "secret-password",
+		Name:     "Reader",
+	})
+	if err != nil {
+		t.Fatalf("Register(%s) failed: %v", email, err)
+	}
+	return user
+}
+
+func login(t *testing.T, useCases *useCases, email string) *model.LoginUserResponse {
+	t.Helper()
+	response, err := useCases.User.Login(context.Background(), &model.LoginUserRequest{
+		Email:    email,
+		Password: "secret-password",
+	})
+	if err != nil {
+		t.Fatalf("Login(%s) failed: %v", email, err)
+	}
+	return response
+}
+
+func TestRegister(t *testing.T) {
+	useCases := newUseCases(t)
+
+	user := register(t, useCases, "reader@example.com")
+	if user.ID == "" || user.Email != "reader@example.com" {
+		t.Fatalf("unexpected user %+v", user)
+	}
+	if !slices.Equal(user.Roles, []string{model.RoleUser}) {
+		t.Fatalf("new user has roles %v, want [%s]", user.Roles, model.RoleUser)
+	}
+
+	_, err := useCases.User.Register(context.Background(), &model.RegisterUserRequest{
+		Email:    "reader@example.com",
+		Password: "another-password",
+		Name:     "Impostor",
+	})
+	if !errors.Is(err, fiber.ErrConflict) {
+		t.Fatalf("registering a taken email returned %v, want %v", err, fiber.ErrConflict)
+	}
+
+	_, err = useCases.User.Register(context.Background(), &model.RegisterUserRequest{Email: "nameless@example.com"})
+	var modelError *model.Error
+	if !errors.As(err, &modelError) || modelError.Code != model.ErrorCodeValidation {
+		t.Fatalf("registering without password and name returned %v, want a validation error", err)
+	}
+}
+
+func TestRegisterConcurrently(t *testing.T) {
+	useCases := newUseCases(t)
+
+	var wait sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			_, err := useCases.User.Register(context.Background(), &model.RegisterUserRequest{
+				Email:    fmt.Sprintf("reader%d@example.com", i),
+				Password: "secret-password",
+				Name:     "Reader",
+			})
+			errs <- err
+		}(i)
+	}
+	wait.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent Register failed: %v", err)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		login(t, useCases, fmt.Sprintf("reader%d@example.com", i))
+	}
+}
+
+func TestLogin(t *testing.T) {
+	useCases := newUseCases(t)
+	user := register(t, useCases, "reader@example.com")
+
+	response := login(t, useCases, "reader@example.com")
+	auth, err := useCases.JwtService.DecodeAuth(response.AccessToken, pkg.ACCESS_TOKEN_KEY)
+	if err != nil {
+		t.Fatalf("access token does not decode: %v", err)
+	}
+	if auth.ID != user.ID || auth.TokenID == "" {
+		t.Fatalf("access token carries %+v, want user %s with a jti", auth, user.ID)
+	}
+	if !auth.HasPermission(model.PermissionBooksRead) || auth.HasPermission(model.PermissionBooksWrite) {
+		t.Fatalf("access token grants %v, want only the permissions of the user role", auth.Permissions)
+	}
+	if err := useCases.User.Verify(context.Background(), auth); err != nil {
+		t.Fatalf("Verify rejected a fresh access token: %v", err)
+	}
+
+	for name, request := range map[string]*model.LoginUserRequest{
+		"wrong password": {Email: "reader@example.com", Password: "wrong-password"},
+		"unknown email":  {Email: "nobody@example.com", Password: "secret-password"},
+	} {
+		if _, err := useCases.User.Login(context.Background(), request); !errors.Is(err, fiber.ErrUnauthorized) {
+			t.Errorf("login with %s returned %v, want %v", name, err, fiber.ErrUnauthorized)
+		}
+	}
+}
+
+func TestUpdateEmail(t *testing.T) {
+	useCases := newUseCases(t)
+	register(t, useCases, "taken@example.com")
+	user := register(t, useCases, "reader@example.com")
+
+	_, err := useCases.User.Update(context.Background(), &model.UpdateUserRequest{ID: user.ID, Email: "taken@example.com"})
+	if !errors.Is(err, fiber.ErrConflict) {
+		t.Fatalf("changing to a taken email returned %v, want %v", err, fiber.ErrConflict)
+	}
+
+	updated, err := useCases.User.Update(context.Background(), &model.UpdateUserRequest{ID: user.ID, Email: "free@example.com"})
+	if err != nil {
+		t.Fatalf("changing to a free email failed: %v", err)
+	}
+	if updated.Email != "free@example.com" {
+		t.Fatalf("updated user has email %s, want free@example.com", updated.Email)
+	}
+}
+
+func TestUpdatePassword(t *testing.T) {
+	useCases := newUseCases(t)
+	user := register(t, useCases, "reader@example.com")
+
+	_, err := useCases.User.Update(context.Background(), &model.UpdateUserRequest{ID: user.ID, Password: "new-password", CurrentPassword: "wrong-password"})
+	var modelError *model.Error
+	if !errors.As(err, &modelError) || modelError.Status != fiber.StatusBadRequest || len(modelError.Fields) != 1 || modelError.Fields[0].Field != "current_password" {
+		t.Fatalf("changing the password with a wrong current one returned %v, want a current_password field error", err)
+	}
+	login(t, useCases, "reader@example.com")
+
+	if _, err := useCases.User.Update(context.Background(), &model.UpdateUserRequest{ID: user.ID, Password: "new-password", CurrentPassword: "secret-password"}); err != nil {
+		t.Fatalf("changing the password failed: %v", err)
+	}
+	if _, err := useCases.User.Login(context.Background(), &model.LoginUserRequest{Email: "reader@example.com", Password: "new-password"}); err != nil {
+		t.Fatalf("login with the new password failed: %v", err)
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	useCases := newUseCases(t)
+	user := register(t, useCases, "reader@example.com")
+	first := login(t, useCases, "reader@example.com")
+	reuses := useCases.Metrics.TokenRefreshes.WithLabelValues(pkg.MetricResultReuse)
+
+	rotated, err := useCases.User.RefreshToken(context.Background(), &model.RefreshTokenRequest{ID: user.ID, Token: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshToken failed: %v", err)
+	}
+	if rotated.RefreshToken == first.RefreshToken || rotated.AccessToken == "" {
+		t.Fatalf("RefreshToken did not rotate the token pair")
+	}
+
+	// replaying the consumed token revokes the whole family, including the
+	// token it was rotated into
+	_, err = useCases.User.RefreshToken(context.Background(), &model.RefreshTokenRequest{ID: user.ID, Token: first.RefreshToken})
+	if !errors.Is(err, fiber.ErrUnauthorized) {
+		t.Fatalf("reusing a refresh token returned %v, want %v", err, fiber.ErrUnauthorized)
+	}
+	if count := testutil.ToFloat64(reuses); count != 1 {
+		t.Fatalf("replaying a token counted %v reuses, want 1", count)
+	}
+
+	// a token that is only revoked is rejected without another reuse event
+	_, err = useCases.User.RefreshToken(context.Background(), &model.RefreshTokenRequest{ID: user.ID, Token: rotated.RefreshToken})
+	if !errors.Is(err, fiber.ErrUnauthorized) {
+		t.Fatalf("refreshing from a revoked family returned %v, want %v", err, fiber.ErrUnauthorized)
+	}
+	if count := testutil.ToFloat64(reuses); count != 1 {
+		t.Fatalf("refreshing from a revoked family counted %v reuses, want 1", count)
+	}
+
+	// a new login starts a new family
+	second := login(t, useCases, "reader@example.com")
+	if _, err := useCases.User.RefreshToken(context.Background(), &model.RefreshTokenRequest{ID: user.ID, Token: second.RefreshToken}); err != nil {
+		t.Fatalf("RefreshToken after a new login failed: %v", err)
+	}
+}
+
+func TestRefreshTokenAfterLogout(t *testing.T) {
+	useCases := newUseCases(t)
+	user := register(t, useCases, "reader@example.com")
+	response := login(t, useCases, "reader@example.com")
+	auth, err := useCases.JwtService.DecodeAuth(response.AccessToken, pkg.ACCESS_TOKEN_KEY)
+	if err != nil {
+		t.Fatalf("access token does not decode: %v", err)
+	}
+
+	if err := useCases.User.Logout(context.Background(), &model.LogoutUserRequest{ID: user.ID, TokenID: auth.TokenID, ExpiresAt: auth.ExpiresAt, RefreshToken: response.RefreshToken}); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	_, err = useCases.User.RefreshToken(context.Background(), &model.RefreshTokenRequest{ID: user.ID, Token: response.RefreshToken})
+	if !errors.Is(err, fiber.ErrUnauthorized) {
+		t.Fatalf("refreshing a logged out token returned %v, want %v", err, fiber.ErrUnauthorized)
+	}
+	if count := testutil.ToFloat64(useCases.Metrics.TokenRefreshes.WithLabelValues(pkg.MetricResultReuse)); count != 0 {
+		t.Fatalf("refreshing a logged out token counted %v reuses, want none", count)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	useCases := newUseCases(t)
+	user := register(t, useCases, "reader@example.com")
+	response := login(t, useCases, "reader@example.com")
+	auth, err := useCases.JwtService.DecodeAuth(response.AccessToken, pkg.ACCESS_TOKEN_KEY)
+	if err != nil {
+		t.Fatalf("access token does not decode: %v", err)
+	}
+
+	request := &model.LogoutUserRequest{ID: user.ID, TokenID: auth.TokenID, ExpiresAt: auth.ExpiresAt, RefreshToken: response.RefreshToken}
+	if err := useCases.User.Logout(context.Background(), request); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if err := useCases.User.Verify(context.Background(), auth); !errors.Is(err, fiber.ErrUnauthorized) {
+		t.Fatalf("Verify after logout returned %v, want %v", err, fiber.ErrUnauthorized)
+	}
+	if _, err := useCases.User.RefreshToken(context.Background(), &model.RefreshTokenRequest{ID: user.ID, Token: response.RefreshToken}); !errors.Is(err, fiber.ErrUnauthorized) {
+		t.Fatalf("refreshing after logout returned %v, want %v", err, fiber.ErrUnauthorized)
+	}
+	if err := useCases.User.Logout(context.Background(), request); err != nil {
+		t.Fatalf("logging out twice failed: %v", err)
+	}
+}
+
+func TestDeleteExpiredTokens(t *testing.T) {
+	useCases := newUseCases(t)
+	user := register(t, useCases, "reader@example.com")
+
+	expired := &model.Auth{ID: user.ID, TokenID: "expired", ExpiresAt: time.Now().Add(-time.Minute)}
+	valid := &model.Auth{ID: user.ID, TokenID: "valid", ExpiresAt: time.Now().Add(time.Hour)}
+	for _, auth := range []*model.Auth{expired, valid} {
+		if err := useCases.User.Logout(context.Background(), &model.LogoutUserRequest{ID: auth.ID, TokenID: auth.TokenID, ExpiresAt: auth.ExpiresAt}); err != nil {
+			t.Fatalf("Logout failed: %v", err)
+		}
+	}
+
+	// logging out leaves the expired entry for the periodic cleanup
+	if err := useCases.User.Verify(context.Background(), expired); !errors.Is(err, fiber.ErrUnauthorized) {
+		t.Fatalf("Verify of a revoked token returned %v, want %v", err, fiber.ErrUnauthorized)
+	}
+	if err := useCases.User.DeleteExpiredTokens(context.Background()); err != nil {
+		t.Fatalf("DeleteExpiredTokens failed: %v", err)
+	}
+	if err := useCases.User.Verify(context.Background(), expired); err != nil {
+		t.Fatalf("the denylist entry of an expired token was kept: %v", err)
+	}
+	if err := useCases.User.Verify(context.Background(), valid); !errors.Is(err, fiber.ErrUnauthorized) {
+		t.Fatalf("Verify of a revoked token that has not expired returned %v, want %v", err, fiber.ErrUnauthorized)
+	}
+}
@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"strings"
+)
+
+// CursorService turns keyset positions into opaque tokens that clients
+// cannot forge or tamper with.
+type CursorService struct {
+	config *model.AppConfig
+}
+
+func NewCursorService(config *model.AppConfig) *CursorService {
+	return &CursorService{config: config}
+}
+
+// Encode serializes the cursor as base64url(payload) "." base64url(hmac).
+func (s *CursorService) Encode(cursor *model.Cursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.sign(encoded), nil
+}
+
+func (s *CursorService) Decode(token string) (*model.Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return nil, fmt.Errorf("invalid cursor signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	cursor := new(model.Cursor)
+	if err := json.Unmarshal(payload, cursor); err != nil {
+		return nil, err
+	}
+	return cursor, nil
+}
+
+func (s *CursorService) sign(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Pagination.CursorSecret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -3,8 +3,9 @@ package pkg
 import (
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/manikandareas/go-clean-architecture/internal/model"
-	"github.com/spf13/viper"
+	"time"
 )
 
 const (
@@ -13,15 +14,45 @@ const (
 )
 
 type JwtService struct {
-	config *viper.Viper
+	settings *Settings
 }
 
-func NewJwtService(config *viper.Viper) *JwtService {
-	return &JwtService{config: config}
+func NewJwtService(settings *Settings) *JwtService {
+	return &JwtService{settings: settings}
 }
 
+// TokenTtl returns the current lifetime of tokens signed with secretKey. It
+// follows configuration reloads, so it must be read for every token issued.
+func (j *JwtService) TokenTtl(secretKey string) time.Duration {
+	if secretKey == REFRESH_TOKEN_KEY {
+		return j.settings.Get().Jwt.RefreshTokenTtl
+	}
+	return j.settings.Get().Jwt.AccessTokenTtl
+}
+
+// secret resolves ACCESS_TOKEN_KEY or REFRESH_TOKEN_KEY to its signing key.
+func (j *JwtService) secret(secretKey string) ([]byte, error) {
+	switch secretKey {
+	case ACCESS_TOKEN_KEY:
+		return []byte(j.settings.Get().Jwt.AccessToken), nil
+	case REFRESH_TOKEN_KEY:
+		return []byte(j.settings.Get().Jwt.RefreshToken), nil
+	default:
+		return nil, fmt.Errorf("unknown jwt secret %q", secretKey)
+	}
+}
+
+// GenerateJwtToken signs the claims, assigning a unique jti first so the
+// token can later be revoked individually.
 func (j *JwtService) GenerateJwtToken(claims *model.JwtClaims, secretKey string) (string, error) {
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, *claims).SignedString([]byte(j.config.GetString(secretKey)))
+	if claims.ID == "" {
+		claims.ID = uuid.NewString()
+	}
+	secret, err := j.secret(secretKey)
+	if err != nil {
+		return "", err
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, *claims).SignedString(secret)
 }
 
 func (j *JwtService) VerifyJwtToken(tokenString string, secretKey string) (*jwt.Token, error) {
@@ -29,7 +60,7 @@ func (j *JwtService) VerifyJwtToken(tokenString string, secretKey string) (*jwt.
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected jwt signing method: %v", token.Header["alg"])
 		}
-		return []byte(j.config.GetString(secretKey)), nil
+		return j.secret(secretKey)
 	})
 }
 
@@ -44,3 +75,41 @@ func (j *JwtService) DecodeJwtToken(tokenString string, secretKey string) (jwt.M
 	}
 	return nil, fmt.Errorf("invalid token")
 }
+
+// DecodeAuth verifies the token and maps its claims to the authenticated user.
+func (j *JwtService) DecodeAuth(tokenString string, secretKey string) (*model.Auth, error) {
+	claims, err := j.DecodeJwtToken(tokenString, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	user, ok := claims["User"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	auth := &model.Auth{}
+	auth.ID, _ = user["user_id"].(string)
+	auth.Email, _ = user["email"].(string)
+	auth.Name, _ = user["name"].(string)
+	auth.TokenID, _ = claims["jti"].(string)
+	auth.Roles = claimStrings(claims["roles"])
+	auth.Permissions = claimStrings(claims["permissions"])
+	if exp, ok := claims["exp"].(float64); ok {
+		auth.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	if auth.ID == "" {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	return auth, nil
+}
+
+func claimStrings(claim interface{}) []string {
+	values, _ := claim.([]interface{})
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,28 @@
+package pkg
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+)
+
+type loggerKey struct{}
+
+// ContextWithLogger returns a copy of ctx carrying entry as the logger of
+// the current request.
+func ContextWithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, entry)
+}
+
+// Logger returns log carrying the fields of the request-scoped entry stored
+// in ctx, or plain log outside of a request. Writing through log rather than
+// the stored entry keeps per-package log levels in effect. The entry is bound
+// to ctx so hooks can read the active span from it.
+func Logger(ctx context.Context, log *logrus.Logger) *logrus.Entry {
+	if entry, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok {
+		if entry.Logger == log {
+			return entry.WithContext(ctx)
+		}
+		return log.WithFields(entry.Data).WithContext(ctx)
+	}
+	return log.WithContext(ctx)
+}
@@ -0,0 +1,118 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	MetricResultSuccess = "success"
+	MetricResultFailure = "failure"
+	MetricResultReuse   = "reuse"
+)
+
+// Metrics owns the Prometheus registry served on /metrics together with the
+// collectors the application reports to.
+type Metrics struct {
+	Registry            *prometheus.Registry
+	HttpRequests        *prometheus.CounterVec
+	HttpRequestDuration *prometheus.HistogramVec
+	DbQueryDuration     *prometheus.HistogramVec
+	Logins              *prometheus.CounterVec
+	TokenRefreshes      *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	metrics := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		HttpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		HttpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route template.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		DbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "GORM statement latency by operation and table.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation", "table"}),
+		Logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_logins_total",
+			Help: "Login attempts by result.",
+		}, []string{"result"}),
+		TokenRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_token_refreshes_total",
+			Help: "Refresh token exchanges by result, reuse marks a replayed token.",
+		}, []string{"result"}),
+	}
+
+	metrics.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		metrics.HttpRequests,
+		metrics.HttpRequestDuration,
+		metrics.DbQueryDuration,
+		metrics.Logins,
+		metrics.TokenRefreshes,
+	)
+	return metrics
+}
+
+// RegisterDatabase exports the connection pool statistics of db as gauges.
+func (m *Metrics) RegisterDatabase(db *gorm.DB, name string) error {
+	connection, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return m.Registry.Register(collectors.NewDBStatsCollector(connection, name))
+}
+
+// GormMetrics is a GORM plugin timing every statement into DbQueryDuration.
+type GormMetrics struct {
+	Metrics *Metrics
+}
+
+const gormMetricsStartKey = "metrics:start"
+
+func (p *GormMetrics) Name() string {
+	return "metrics"
+}
+
+func (p *GormMetrics) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("metrics:before_create", p.before),
+		callback.Create().After("gorm:create").Register("metrics:after_create", p.after("create")),
+		callback.Query().Before("gorm:query").Register("metrics:before_query", p.before),
+		callback.Query().After("gorm:query").Register("metrics:after_query", p.after("query")),
+		callback.Update().Before("gorm:update").Register("metrics:before_update", p.before),
+		callback.Update().After("gorm:update").Register("metrics:after_update", p.after("update")),
+		callback.Delete().Before("gorm:delete").Register("metrics:before_delete", p.before),
+		callback.Delete().After("gorm:delete").Register("metrics:after_delete", p.after("delete")),
+		callback.Row().Before("gorm:row").Register("metrics:before_row", p.before),
+		callback.Row().After("gorm:row").Register("metrics:after_row", p.after("row")),
+		callback.Raw().Before("gorm:raw").Register("metrics:before_raw", p.before),
+		callback.Raw().After("gorm:raw").Register("metrics:after_raw", p.after("raw")),
+	)
+}
+
+func (p *GormMetrics) before(db *gorm.DB) {
+	db.InstanceSet(gormMetricsStartKey, time.Now())
+}
+
+func (p *GormMetrics) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(gormMetricsStartKey)
+		if !ok {
+			return
+		}
+		start, _ := value.(time.Time)
+		p.Metrics.DbQueryDuration.WithLabelValues(operation, db.Statement.Table).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"regexp"
+	"strings"
+)
+
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are log field names whose values are always masked.
+var sensitiveKeys = map[string]bool{
+	"password":         true,
+	"current_password": true,
+	"token":            true,
+	"access_token":     true,
+	"refresh_token":    true,
+	"token_hash":       true,
+	"secret":           true,
+	"authorization":    true,
+	"cookie":           true,
+	"set-cookie":       true,
+}
+
+var (
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	schemePattern = regexp.MustCompile(`(?i)\b(Bearer|Refresh|Basic) +[A-Za-z0-9._~+/=-]{8,}`)
+	bcryptPattern = regexp.MustCompile(`\$2[abxy]\$\d{2}\$[./A-Za-z0-9]{53}`)
+	// key=value, "key":"value" and %+v struct fields, but not the "Label : value"
+	// style of log messages, whose values the other patterns cover
+	pairPattern   = regexp.MustCompile(`(?i)("?(?:current_password|password|access_token|refresh_token|token|secret)"?[:=]\s*)("[^"]*"|[^\s,}&]+)`)
+	digestPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// IsSensitiveKey reports whether values logged under key must be masked.
+func IsSensitiveKey(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+// Redact masks tokens, credentials and password hashes inside free text.
+func Redact(text string) string {
+	text = jwtPattern.ReplaceAllString(text, Redacted)
+	text = schemePattern.ReplaceAllString(text, "$1 "+Redacted)
+	text = bcryptPattern.ReplaceAllString(text, Redacted)
+	return pairPattern.ReplaceAllString(text, "${1}"+Redacted)
+}
+
+// RedactValue masks a single value such as a SQL parameter: strings that are
+// entirely a token, hash or digest are replaced, other text is redacted.
+func RedactValue(value interface{}) interface{} {
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+	if digestPattern.MatchString(text) {
+		return Redacted
+	}
+	return Redact(text)
+}
+
+// RedactHook applies Redact to every entry before it is written, so no log
+// call site has to remember to.
+type RedactHook struct{}
+
+func (h *RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = Redact(entry.Message)
+	for key, value := range entry.Data {
+		if IsSensitiveKey(key) {
+			entry.Data[key] = Redacted
+			continue
+		}
+		switch value := value.(type) {
+		case string:
+			entry.Data[key] = Redact(value)
+		case error:
+			if message := Redact(value.Error()); message != value.Error() {
+				entry.Data[key] = errors.New(message)
+			}
+		}
+	}
+	return nil
+}
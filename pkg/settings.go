@@ -0,0 +1,27 @@
+package pkg
+
+import (
+	"github.com/manikandareas/go-clean-architecture/internal/model"
+	"sync/atomic"
+)
+
+// Settings shares the current configuration between the request handlers and
+// the config watcher, which swaps in a new snapshot when the file changes.
+// Snapshots are never modified once stored.
+type Settings struct {
+	current atomic.Pointer[model.AppConfig]
+}
+
+func NewSettings(config *model.AppConfig) *Settings {
+	settings := new(Settings)
+	settings.current.Store(config)
+	return settings
+}
+
+func (s *Settings) Get() *model.AppConfig {
+	return s.current.Load()
+}
+
+func (s *Settings) Set(config *model.AppConfig) {
+	s.current.Store(config)
+}